@@ -0,0 +1,275 @@
+package lib
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 认证方式
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+// MirrorAuth 镜像源的认证设置
+// 凭据只从环境变量或~/.netrc读取，配置文件中只写环境变量的名称，不保存密码本身
+type MirrorAuth struct {
+	Type        string `yaml:"type,omitempty"`         // basic 或 bearer，未设置时根据其他字段推断
+	UsernameEnv string `yaml:"username_env,omitempty"` // 保存用户名的环境变量
+	PasswordEnv string `yaml:"password_env,omitempty"` // 保存密码的环境变量
+	TokenEnv    string `yaml:"token_env,omitempty"`    // 保存Bearer令牌的环境变量
+	Netrc       bool   `yaml:"netrc,omitempty"`        // 从~/.netrc中读取该主机的用户名和密码（bearer时密码作为令牌）
+}
+
+// 获取认证方式
+func (a *MirrorAuth) authType() string {
+	if a.Type != "" {
+		return strings.ToLower(a.Type)
+	}
+	if a.TokenEnv != "" {
+		return AuthBearer
+	}
+	return AuthBasic
+}
+
+// 检查认证设置是否完整
+func (a *MirrorAuth) validate() error {
+	switch a.authType() {
+	case AuthBasic:
+		if !a.Netrc && (a.UsernameEnv == "" || a.PasswordEnv == "") {
+			return errors.New(T("basic认证需要同时设置username_env和password_env，或设置netrc: true"))
+		}
+	case AuthBearer:
+		if !a.Netrc && a.TokenEnv == "" {
+			return errors.New(T("bearer认证需要设置token_env，或设置netrc: true"))
+		}
+	default:
+		return fmt.Errorf(T("不支持的认证方式: %s（支持 basic、bearer）"), a.Type)
+	}
+	return nil
+}
+
+// 描述认证设置，只包含环境变量名称，不包含凭据
+func (a *MirrorAuth) String() string {
+	var sources []string
+	for _, name := range []string{a.UsernameEnv, a.PasswordEnv, a.TokenEnv} {
+		if name != "" {
+			sources = append(sources, "$"+name)
+		}
+	}
+	if a.Netrc {
+		sources = append(sources, "~/.netrc")
+	}
+	return fmt.Sprintf("%s (%s)", a.authType(), strings.Join(sources, ", "))
+}
+
+// 读取环境变量中的凭据，未设置时返回错误
+func lookupCredentialEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf(T("认证所需的环境变量 %s 未设置"), name)
+	}
+	return value, nil
+}
+
+// 生成访问host时使用的Authorization请求头
+// 开启netrc时先从~/.netrc读取，设置了环境变量的部分以环境变量为准
+func (a *MirrorAuth) header(host string) (string, error) {
+	var login, password string
+	if a.Netrc {
+		entry, err := lookupNetrc(host)
+		if err != nil {
+			return "", err
+		}
+		login, password = entry.login, entry.password
+	}
+
+	if a.authType() == AuthBearer {
+		token := password
+		if a.TokenEnv != "" {
+			value, err := lookupCredentialEnv(a.TokenEnv)
+			if err != nil {
+				return "", err
+			}
+			token = value
+		}
+		if token == "" {
+			return "", fmt.Errorf(T("%s 中主机 %s 的记录没有令牌"), netrcPath(), host)
+		}
+		return "Bearer " + token, nil
+	}
+
+	if a.UsernameEnv != "" {
+		value, err := lookupCredentialEnv(a.UsernameEnv)
+		if err != nil {
+			return "", err
+		}
+		login = value
+	}
+	if a.PasswordEnv != "" {
+		value, err := lookupCredentialEnv(a.PasswordEnv)
+		if err != nil {
+			return "", err
+		}
+		password = value
+	}
+	// 只能来自.netrc：环境变量未设置或为空时已经返回错误
+	if login == "" && password == "" {
+		return "", fmt.Errorf(T("%s 中主机 %s 的记录没有用户名和密码"), netrcPath(), host)
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(login+":"+password)), nil
+}
+
+// 为发往镜像源主机的请求添加认证信息
+// 重定向到其他主机（如对象存储）时不发送凭据
+type authTransport struct {
+	base http.RoundTripper
+	auth *MirrorAuth
+	host string // 镜像源主机名（含端口）
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+
+	header, err := t.auth.header(req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	// RoundTripper不能修改原请求
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", header)
+	return t.base.RoundTrip(req)
+}
+
+// 获取镜像源URL模板中的主机名（含端口）
+func mirrorHost(mirror Mirror) string {
+	u, err := url.Parse(mirror.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// RedactURL 隐藏URL（或含 {{version}} 的URL模板）中的密码，便于显示和记录
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); !ok {
+		return raw
+	}
+	// 不使用url.Redacted()，避免模板中的 {{version}} 被转义
+	return strings.Replace(raw, u.User.String()+"@", url.UserPassword(u.User.Username(), "xxxxx").String()+"@", 1)
+}
+
+// 镜像源返回401/403时，说明认证失败及可能的原因
+func explainAuthError(mirror Mirror, err error) error {
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) || (statusErr.code != http.StatusUnauthorized && statusErr.code != http.StatusForbidden) {
+		return err
+	}
+	if mirror.Auth == nil {
+		return fmt.Errorf(T("认证失败（HTTP %d）: 镜像源 %s 需要认证，请在配置文件中为其设置auth"), statusErr.code, mirror.Name)
+	}
+	return fmt.Errorf(T("认证失败（HTTP %d）: 镜像源 %s 拒绝了凭据 %s，请检查环境变量或~/.netrc中的用户名、密码或令牌"), statusErr.code, mirror.Name, mirror.Auth)
+}
+
+// .netrc中的一条记录
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// 获取.netrc文件路径：优先使用NETRC环境变量，Windows上默认为~/_netrc
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// 在.netrc中查找主机对应的记录，没有匹配的machine时使用default
+func lookupNetrc(host string) (netrcEntry, error) {
+	path := netrcPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return netrcEntry{}, fmt.Errorf(T("读取.netrc失败: %v"), err)
+	}
+
+	// 去掉macdef宏定义（到空行为止），其余内容按空白分词
+	var tokens []string
+	inMacro := false
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if inMacro {
+			inMacro = len(fields) > 0
+			continue
+		}
+		if len(fields) > 0 && fields[0] == "macdef" {
+			inMacro = true
+			continue
+		}
+		tokens = append(tokens, fields...)
+	}
+
+	var found, fallback, current *netrcEntry
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			current = nil
+			if i+1 < len(tokens) {
+				i++
+				// 主机名不区分大小写
+				if strings.EqualFold(tokens[i], host) && found == nil {
+					found = &netrcEntry{}
+					current = found
+				}
+			}
+		case "default":
+			current = nil
+			if fallback == nil {
+				fallback = &netrcEntry{}
+				current = fallback
+			}
+		case "login", "password", "account":
+			if i+1 >= len(tokens) {
+				continue
+			}
+			i++
+			if current == nil {
+				continue
+			}
+			switch tokens[i-1] {
+			case "login":
+				current.login = tokens[i]
+			case "password":
+				current.password = tokens[i]
+			}
+		}
+	}
+
+	switch {
+	case found != nil:
+		return *found, nil
+	case fallback != nil:
+		return *fallback, nil
+	}
+	return netrcEntry{}, fmt.Errorf(T(".netrc中没有主机 %s 的记录: %s"), host, path)
+}
@@ -0,0 +1,103 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookupNetrc(t *testing.T) {
+	const netrc = `machine repo.example.com
+  login alice
+  password secret1
+
+machine other.example.com login bob password secret2 account ops
+
+macdef init
+  machine repo.example.com login macro password macro
+
+machine repo.example.com login dup password dup
+default login anonymous password guest
+`
+	tests := []struct {
+		name    string
+		content string
+		host    string
+		want    netrcEntry
+		wantErr bool
+	}{
+		{name: "machine", content: netrc, host: "repo.example.com", want: netrcEntry{login: "alice", password: "secret1"}},
+		{name: "single line with account", content: netrc, host: "other.example.com", want: netrcEntry{login: "bob", password: "secret2"}},
+		{name: "default", content: netrc, host: "unknown.example.com", want: netrcEntry{login: "anonymous", password: "guest"}},
+		{name: "host is case insensitive", content: netrc, host: "REPO.Example.com", want: netrcEntry{login: "alice", password: "secret1"}},
+		{name: "macdef skipped", content: "macdef init\n  machine m login macro password macro\n\nmachine m login real password real\n", host: "m", want: netrcEntry{login: "real", password: "real"}},
+		{name: "token only", content: "machine m password token\n", host: "m", want: netrcEntry{password: "token"}},
+		{name: "trailing keyword", content: "machine m login", host: "m", want: netrcEntry{}},
+		{name: "no match", content: "machine a login x password y\n", host: "b", wantErr: true},
+		{name: "empty", content: "", host: "m", wantErr: true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), ".netrc")
+		if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("NETRC", path)
+
+		got, err := lookupNetrc(tt.host)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: lookupNetrc(%q) error = %v, wantErr %v", tt.name, tt.host, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: lookupNetrc(%q) = %+v, want %+v", tt.name, tt.host, got, tt.want)
+		}
+	}
+
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "missing"))
+	if _, err := lookupNetrc("m"); err == nil {
+		t.Error("lookupNetrc with a missing file: want error")
+	}
+}
+
+func TestMirrorAuthHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".netrc")
+	content := "machine repo.example.com login alice password secret1\n" +
+		"machine token.example.com password tok\n" +
+		"machine empty.example.com\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NETRC", path)
+	t.Setenv("TEST_MIRROR_USER", "bob")
+	t.Setenv("TEST_MIRROR_PASS", "secret2")
+	t.Setenv("TEST_MIRROR_TOKEN", "envtoken")
+
+	tests := []struct {
+		name    string
+		auth    MirrorAuth
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{name: "basic env", auth: MirrorAuth{UsernameEnv: "TEST_MIRROR_USER", PasswordEnv: "TEST_MIRROR_PASS"}, host: "repo.example.com", want: "Basic Ym9iOnNlY3JldDI="},
+		{name: "basic netrc", auth: MirrorAuth{Netrc: true}, host: "repo.example.com", want: "Basic YWxpY2U6c2VjcmV0MQ=="},
+		{name: "netrc host case", auth: MirrorAuth{Netrc: true}, host: "Repo.Example.COM", want: "Basic YWxpY2U6c2VjcmV0MQ=="},
+		{name: "env overrides netrc", auth: MirrorAuth{Netrc: true, PasswordEnv: "TEST_MIRROR_PASS"}, host: "repo.example.com", want: "Basic YWxpY2U6c2VjcmV0Mg=="},
+		{name: "bearer env", auth: MirrorAuth{TokenEnv: "TEST_MIRROR_TOKEN"}, host: "repo.example.com", want: "Bearer envtoken"},
+		{name: "bearer netrc", auth: MirrorAuth{Type: AuthBearer, Netrc: true}, host: "token.example.com", want: "Bearer tok"},
+		{name: "basic netrc without credentials", auth: MirrorAuth{Netrc: true}, host: "empty.example.com", wantErr: true},
+		{name: "bearer netrc without token", auth: MirrorAuth{Type: AuthBearer, Netrc: true}, host: "empty.example.com", wantErr: true},
+		{name: "netrc no match", auth: MirrorAuth{Netrc: true}, host: "other.example.com", wantErr: true},
+		{name: "env not set", auth: MirrorAuth{UsernameEnv: "TEST_MIRROR_UNSET", PasswordEnv: "TEST_MIRROR_PASS"}, host: "repo.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.auth.header(tt.host)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: header(%q) error = %v, wantErr %v", tt.name, tt.host, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: header(%q) = %q, want %q", tt.name, tt.host, got, tt.want)
+		}
+	}
+}
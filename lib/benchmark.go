@@ -0,0 +1,229 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 测速相关参数
+const (
+	benchmarkVersion     = "8.7"            // 测速使用的Gradle版本
+	benchmarkSampleBytes = 1 << 20          // 每个镜像最多采样下载的字节数
+	benchmarkTimeout     = 15 * time.Second // 单个镜像测速的总超时时间
+	rankingMaxAge        = 7 * 24 * time.Hour
+)
+
+// MirrorBenchmark 单个镜像源的测速结果
+type MirrorBenchmark struct {
+	Name       string        `json:"name"`
+	Available  bool          `json:"available"`
+	TTFB       time.Duration `json:"ttfb"`       // 首字节时间
+	Throughput float64       `json:"throughput"` // 采样吞吐量（字节/秒）
+	Error      string        `json:"error,omitempty"`
+}
+
+// 持久化的镜像源排名
+type mirrorRanking struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Mirrors   []string  `json:"mirrors"` // 按速度从快到慢排列的可用镜像名称
+}
+
+// 获取镜像源排名文件路径
+func getRankingPath() string {
+	return filepath.Join(getAppDir(), "mirror-ranking.json")
+}
+
+// 对单个镜像测速：测量首字节时间，并在限定字节数/时间内采样吞吐量
+func benchmarkMirror(ctx context.Context, mirror Mirror) MirrorBenchmark {
+	result := MirrorBenchmark{Name: mirror.Name}
+
+	// 同时提供两种版本的镜像以bin版测速
+	edition := "bin"
+	if !mirror.supportsEdition(edition) {
+		edition = "all"
+	}
+	url := mirror.distributionURL(benchmarkVersion, edition)
+
+	client := newHTTPClient(mirror, benchmarkTimeout)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	setRequestHeaders(req)
+	// 只请求采样所需的部分，不支持Range的服务器会返回完整文件，由下方限制读取量
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", benchmarkSampleBytes-1))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = explainTLSError(err).Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		result.Error = explainAuthError(mirror, &httpStatusError{code: resp.StatusCode}).Error()
+		return result
+	}
+
+	// 读取第一个字节以测量首字节时间
+	first := make([]byte, 1)
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.TTFB = time.Since(start)
+
+	// 采样吞吐量，超时只会缩短采样，不视为失败
+	sampleStart := time.Now()
+	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, benchmarkSampleBytes-1))
+	elapsed := time.Since(sampleStart)
+	if err != nil && n == 0 {
+		result.Error = err.Error()
+		return result
+	}
+	if elapsed > 0 {
+		result.Throughput = float64(n+1) / elapsed.Seconds()
+	}
+
+	result.Available = true
+	return result
+}
+
+// BenchmarkMirrors 并发测速所有启用的镜像源，返回按速度排序的结果，并保存排名
+// ctx取消时返回ctx.Err()，不保存不完整的排名
+func BenchmarkMirrors(ctx context.Context) ([]MirrorBenchmark, error) {
+	list := enabledMirrors()
+	results := make([]MirrorBenchmark, len(list))
+
+	var wg sync.WaitGroup
+	for i, mirror := range list {
+		wg.Add(1)
+		go func(i int, mirror Mirror) {
+			defer wg.Done()
+			results[i] = benchmarkMirror(ctx, mirror)
+		}(i, mirror)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rankBenchmarks(results)
+
+	if err := saveMirrorRanking(results); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// 排序规则：可用的在前，吞吐量高的在前，吞吐量相同时首字节时间短的在前
+func rankBenchmarks(results []MirrorBenchmark) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Available != b.Available {
+			return a.Available
+		}
+		if a.Throughput != b.Throughput {
+			return a.Throughput > b.Throughput
+		}
+		return a.TTFB < b.TTFB
+	})
+}
+
+// 保存可用镜像的排名，供后续下载时优先使用最快的镜像
+// 没有任何可用镜像时（如网络断开）保留上次的排名
+func saveMirrorRanking(results []MirrorBenchmark) error {
+	ranking := mirrorRanking{UpdatedAt: time.Now()}
+	for _, result := range results {
+		if result.Available {
+			ranking.Mirrors = append(ranking.Mirrors, result.Name)
+		}
+	}
+	if len(ranking.Mirrors) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(ranking, "", "  ")
+	if err != nil {
+		return fmt.Errorf(T("保存镜像源排名失败: %v"), err)
+	}
+	if err := os.MkdirAll(getAppDir(), os.ModePerm); err != nil {
+		return fmt.Errorf(T("保存镜像源排名失败: %v"), err)
+	}
+	if err := writeFileAtomic(getRankingPath(), data, 0644); err != nil {
+		return fmt.Errorf(T("保存镜像源排名失败: %v"), err)
+	}
+	return nil
+}
+
+// 读取镜像源排名，文件不存在、损坏或已过期时返回nil
+func loadMirrorRanking() []string {
+	data, err := os.ReadFile(getRankingPath())
+	if err != nil {
+		return nil
+	}
+
+	var ranking mirrorRanking
+	if err := json.Unmarshal(data, &ranking); err != nil {
+		return nil
+	}
+	if time.Since(ranking.UpdatedAt) > rankingMaxAge {
+		return nil
+	}
+	return ranking.Mirrors
+}
+
+// 按上次测速的排名排列镜像源，未参与排名的镜像保持原有顺序排在后面
+func rankedMirrors(list []Mirror) []Mirror {
+	if mirrorsOverridden {
+		return list
+	}
+
+	ranking := loadMirrorRanking()
+	if len(ranking) == 0 {
+		return list
+	}
+
+	rank := make(map[string]int, len(ranking))
+	for i, name := range ranking {
+		rank[name] = i
+	}
+
+	sorted := make([]Mirror, len(list))
+	copy(sorted, list)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, okI := rank[sorted[i].Name]
+		rj, okJ := rank[sorted[j].Name]
+		if okI != okJ {
+			return okI
+		}
+		return okI && ri < rj
+	})
+	return sorted
+}
+
+// FormatBytes 将字节数格式化为易读的字符串
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
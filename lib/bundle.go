@@ -0,0 +1,338 @@
+package lib
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 离线包中清单文件的名称，位于离线包的第一个文件
+const bundleManifestName = "manifest.json"
+
+// 当前的离线包格式版本，格式不兼容地变化时递增
+const bundleFormatVersion = 1
+
+// BundleManifest 离线包的清单，记录其中每个发行包的版本和校验和
+type BundleManifest struct {
+	Format    int           `json:"format"`
+	CreatedAt time.Time     `json:"created_at"`
+	Entries   []BundleEntry `json:"entries"`
+}
+
+// BundleEntry 离线包中的单个发行包
+type BundleEntry struct {
+	Version string `json:"version"`
+	Edition string `json:"edition"`
+	File    string `json:"file"` // 离线包中的文件名，例如 gradle-8.7-bin.zip
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// 检查清单内容是否有效，文件名必须与版本一致，避免写入意外的路径
+func (m *BundleManifest) validate() error {
+	if m.Format > bundleFormatVersion {
+		return fmt.Errorf(T("不支持的离线包格式版本: %d，请升级本程序"), m.Format)
+	}
+	if len(m.Entries) == 0 {
+		return errors.New(T("离线包清单中没有发行包"))
+	}
+	seen := make(map[string]bool)
+	for _, entry := range m.Entries {
+		if entry.File != fmt.Sprintf("gradle-%s-%s.zip", entry.Version, entry.Edition) {
+			return fmt.Errorf(T("离线包清单中的文件名无效: %s"), entry.File)
+		}
+		if _, _, err := extractGradleVersion(entry.File); err != nil {
+			return err
+		}
+		if !sha256Pattern.MatchString(entry.SHA256) || len(entry.SHA256) != 64 {
+			return fmt.Errorf(T("离线包清单中 %s 的校验和无效: %s"), entry.File, entry.SHA256)
+		}
+		if seen[entry.File] {
+			return fmt.Errorf(T("离线包清单中的文件重复: %s"), entry.File)
+		}
+		seen[entry.File] = true
+	}
+	return nil
+}
+
+// 查找清单中的发行包，不存在时返回nil
+func (m *BundleManifest) find(file string) *BundleEntry {
+	for i := range m.Entries {
+		if m.Entries[i].File == file {
+			return &m.Entries[i]
+		}
+	}
+	return nil
+}
+
+// ExportBundle 将缓存中的发行包打包为离线包（tar格式），用于分发给无法联网的电脑
+// versions为要导出的Gradle版本号，为空时导出全部缓存；edition非空时只导出该版本类型
+// 导出前重新校验每个发行包，指定的版本不在缓存中或校验失败时不生成离线包
+func ExportBundle(ctx context.Context, versions []string, edition, output string, reporter Reporter) ([]BundleEntry, error) {
+	for _, version := range versions {
+		if _, err := ParseGradleVersion(version); err != nil {
+			return nil, err
+		}
+	}
+	if edition != "" {
+		if err := ValidateEdition(edition); err != nil {
+			return nil, err
+		}
+	}
+
+	cached, err := ListCacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	// 按版本号和版本类型筛选
+	var selected []CacheEntry
+	for _, entry := range cached {
+		if len(versions) > 0 && !slices.Contains(versions, entry.Version) {
+			continue
+		}
+		if edition != "" && entry.Edition != edition {
+			continue
+		}
+		selected = append(selected, entry)
+	}
+	for _, version := range versions {
+		if !slices.ContainsFunc(selected, func(entry CacheEntry) bool { return entry.Version == version }) {
+			return nil, fmt.Errorf(T("缓存中没有Gradle %s，请先下载或导入"), version)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New(T("缓存中没有可导出的发行包"))
+	}
+
+	manifest := BundleManifest{Format: bundleFormatVersion, CreatedAt: time.Now().UTC()}
+	for _, entry := range selected {
+		reportMessage(reporter, T("正在校验 %s ...\n", entry.File))
+		if err := VerifyCachedGradle(entry.Version, entry.Edition, ""); err != nil {
+			return nil, fmt.Errorf(T("缓存中的Gradle %s %s版 校验失败: %v"), entry.Version, entry.Edition, err)
+		}
+		manifest.Entries = append(manifest.Entries, BundleEntry{
+			Version: entry.Version,
+			Edition: entry.Edition,
+			File:    fmt.Sprintf("gradle-%s-%s.zip", entry.Version, entry.Edition),
+			Size:    entry.Size,
+			SHA256:  strings.ToLower(entry.SHA256),
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf(T("生成离线包清单失败: %v"), err)
+	}
+
+	// 清单放在第一个，导入时先读取清单再逐个校验发行包
+	err = writeAtomic(output, 0644, func(w io.Writer) error {
+		tw := tar.NewWriter(w)
+		header := &tar.Header{Name: bundleManifestName, Mode: 0644, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+
+		for _, entry := range manifest.Entries {
+			if err := writeBundleEntry(ctx, tw, entry, reporter); err != nil {
+				return err
+			}
+		}
+		return tw.Close()
+	})
+	if err != nil {
+		return nil, fmt.Errorf(T("写入离线包失败: %v"), err)
+	}
+	return manifest.Entries, nil
+}
+
+// 将缓存中的发行包写入离线包
+func writeBundleEntry(ctx context.Context, tw *tar.Writer, entry BundleEntry, reporter Reporter) error {
+	sourcePath := CachedGradlePath(entry.Version, entry.Edition)
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != entry.Size {
+		return fmt.Errorf(T("文件大小不一致: 期望 %d 字节, 实际 %d 字节"), entry.Size, info.Size())
+	}
+
+	header := &tar.Header{Name: entry.File, Mode: 0644, Size: entry.Size, ModTime: info.ModTime()}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	report(reporter, Event{Type: EventStarted, Task: TaskCopy, Path: entry.File, Total: entry.Size})
+	progress := newProgressWriter(reporter, TaskCopy, entry.File, 0, entry.Size)
+	if _, err := io.Copy(io.MultiWriter(tw, progress), &contextReader{ctx: ctx, r: source}); err != nil {
+		report(reporter, Event{Type: EventDone, Task: TaskCopy, Path: entry.File, Error: err.Error()})
+		return err
+	}
+	report(reporter, Event{Type: EventDone, Task: TaskCopy, Path: entry.File, Current: entry.Size, Total: entry.Size,
+		Message: T("✅ 已导出: %s\n", entry.File)})
+	return nil
+}
+
+// ImportBundle 导入 ExportBundle 生成的离线包：按清单校验每个发行包的大小和SHA-256，再导入缓存
+// distsDir非空时，还按官方distributionUrl对应的Wrapper目录把发行包安装到MCreator的dists目录，
+// unpack为true时同时解压；整个过程不访问网络
+func ImportBundle(ctx context.Context, bundlePath, distsDir string, unpack bool, reporter Reporter) ([]ImportResult, error) {
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf(T("打开离线包失败: %v"), err)
+	}
+	defer file.Close()
+
+	tr := tar.NewReader(file)
+	manifest, err := readBundleManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解压到缓存目录下的临时目录，校验通过后再导入缓存
+	if err := os.MkdirAll(GetCacheDir(), os.ModePerm); err != nil {
+		return nil, fmt.Errorf(T("创建缓存目录失败: %v"), err)
+	}
+	tempDir, err := os.MkdirTemp(GetCacheDir(), ".bundle-")
+	if err != nil {
+		return nil, fmt.Errorf(T("创建临时目录失败: %v"), err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	results := make(map[string]ImportResult)
+	for {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(T("读取离线包失败: %v"), err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		entry := manifest.find(header.Name)
+		if entry == nil {
+			reportMessage(reporter, T("⚠️ 跳过清单中没有的文件: %s\n", header.Name))
+			continue
+		}
+		if _, ok := results[entry.File]; ok {
+			continue
+		}
+		results[entry.File] = importBundleEntry(ctx, tr, header, *entry, tempDir, reporter)
+	}
+
+	// 按清单顺序整理结果，离线包中缺少的文件视为失败
+	var list []ImportResult
+	failed := 0
+	for _, entry := range manifest.Entries {
+		result, ok := results[entry.File]
+		if !ok {
+			result = ImportResult{File: entry.File, Version: entry.Version, Edition: entry.Edition, SHA256: entry.SHA256}
+			if ctx.Err() != nil {
+				result.Status = ImportSkipped
+			} else {
+				result.Status = ImportFailed
+				result.Error = T("离线包中缺少该文件")
+			}
+		}
+
+		if distsDir != "" && (result.Status == ImportOK || result.Status == ImportExists) {
+			result.TargetDir, err = installBundleEntry(ctx, distsDir, entry, unpack, reporter)
+			if err != nil {
+				result.Status = ImportFailed
+				result.Error = err.Error()
+			}
+		}
+		if result.Status == ImportFailed {
+			reportMessage(reporter, T("❌ %s 导入失败: %s\n", entry.File, result.Error))
+			failed++
+		}
+		list = append(list, result)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return list, err
+	}
+	if failed > 0 {
+		return list, fmt.Errorf(T("%d 个发行包导入失败"), failed)
+	}
+	return list, nil
+}
+
+// 读取并检查离线包开头的清单
+func readBundleManifest(tr *tar.Reader) (*BundleManifest, error) {
+	header, err := tr.Next()
+	if err != nil || header.Name != bundleManifestName {
+		return nil, fmt.Errorf(T("不是有效的离线包: 缺少%s"), bundleManifestName)
+	}
+
+	var manifest BundleManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf(T("离线包清单格式无效: %v"), err)
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// 从离线包中取出单个发行包，核对大小后按清单中的校验和导入缓存
+func importBundleEntry(ctx context.Context, r io.Reader, header *tar.Header, entry BundleEntry, tempDir string, reporter Reporter) ImportResult {
+	fail := func(err error) ImportResult {
+		return ImportResult{File: entry.File, Version: entry.Version, Edition: entry.Edition, SHA256: entry.SHA256,
+			Status: ImportFailed, Error: err.Error()}
+	}
+
+	if header.Size != entry.Size {
+		return fail(fmt.Errorf(T("文件大小不一致: 期望 %d 字节, 实际 %d 字节"), entry.Size, header.Size))
+	}
+
+	zipPath := filepath.Join(tempDir, entry.File)
+	err := writeAtomic(zipPath, 0644, func(w io.Writer) error {
+		_, err := io.Copy(w, &contextReader{ctx: ctx, r: r})
+		return err
+	})
+	if err != nil {
+		return fail(fmt.Errorf(T("读取离线包失败: %v"), err))
+	}
+
+	result := importGradleZip(ctx, zipPath, entry.SHA256, reporter)
+	result.File, result.Version, result.Edition = entry.File, entry.Version, entry.Edition
+	os.Remove(zipPath)
+	return result
+}
+
+// 按官方distributionUrl把缓存中的发行包安装到dists目录，返回安装目录
+func installBundleEntry(ctx context.Context, distsDir string, entry BundleEntry, unpack bool, reporter Reporter) (string, error) {
+	distributionURL := OfficialDistributionURL(entry.Version, entry.Edition)
+	targetDir, err := WrapperDistributionDir(distsDir, distributionURL)
+	if err != nil {
+		return "", err
+	}
+	if err := InstallFromURL(ctx, distsDir, distributionURL, entry.SHA256, unpack, reporter); err != nil {
+		return targetDir, fmt.Errorf(T("安装到dists目录失败: %v"), err)
+	}
+	return targetDir, nil
+}
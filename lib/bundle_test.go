@@ -0,0 +1,216 @@
+package lib
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 使用临时目录作为用户主目录，缓存等数据都写入其中
+func useTempHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+// 生成离线包：先写入清单，再按顺序写入files中的文件
+func writeBundle(t *testing.T, path string, manifest BundleManifest, files ...[2]string) {
+	t.Helper()
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, file := range append([][2]string{{bundleManifestName, string(data)}}, files...) {
+		if err := tw.WriteHeader(&tar.Header{Name: file[0], Mode: 0644, Size: int64(len(file[1]))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(file[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 离线包清单中的一项，校验和与大小按content计算
+func bundleEntry(t *testing.T, version, file, content string) BundleEntry {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "entry.zip")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := FileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return BundleEntry{Version: version, Edition: "bin", File: file, Size: int64(len(content)), SHA256: sum}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	useTempHome(t)
+	SetOffline(true)
+	defer SetOffline(false)
+
+	source := t.TempDir()
+	writeZip(t, source, "gradle-8.6-bin.zip", "gradle-8.6/lib/gradle-core-8.6.jar")
+	writeZip(t, source, "gradle-8.7-all.zip", "gradle-8.7/lib/gradle-core-8.7.jar", "gradle-8.7/src/Main.java")
+	if _, err := ImportGradle(context.Background(), source, nil); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ListCacheEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "gradle.tar")
+	entries, err := ExportBundle(context.Background(), nil, "", bundlePath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("exported %d distributions, want 2", len(entries))
+	}
+
+	// 在另一台电脑（新的主目录）上导入，并安装到dists目录
+	useTempHome(t)
+	distsDir := t.TempDir()
+	results, err := ImportBundle(context.Background(), bundlePath, distsDir, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want 2", results)
+	}
+	for _, result := range results {
+		if result.Status != ImportOK {
+			t.Errorf("%s: status = %s (%s), want %s", result.File, result.Status, result.Error, ImportOK)
+		}
+		if _, err := os.Stat(filepath.Join(result.TargetDir, result.File)); err != nil {
+			t.Errorf("%s not installed into the dists directory: %v", result.File, err)
+		}
+	}
+
+	imported, err := ListCacheEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != len(exported) {
+		t.Fatalf("imported %d cache entries, want %d", len(imported), len(exported))
+	}
+	for _, entry := range exported {
+		if err := VerifyCachedGradle(entry.Version, entry.Edition, entry.SHA256); err != nil {
+			t.Errorf("Gradle %s %s: %v", entry.Version, entry.Edition, err)
+		}
+	}
+
+	// 再次导入时缓存中已有相同的发行包
+	results, err = ImportBundle(context.Background(), bundlePath, "", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != ImportExists {
+			t.Errorf("%s: status = %s, want %s", result.File, result.Status, ImportExists)
+		}
+	}
+}
+
+func TestExportBundleMissingVersion(t *testing.T) {
+	useTempHome(t)
+	bundlePath := filepath.Join(t.TempDir(), "gradle.tar")
+	if _, err := ExportBundle(context.Background(), []string{"8.7"}, "", bundlePath, nil); err == nil {
+		t.Fatal("want error for a version that is not cached")
+	}
+	if _, err := os.Stat(bundlePath); !os.IsNotExist(err) {
+		t.Error("bundle written although the export failed")
+	}
+}
+
+func TestImportBundleRejected(t *testing.T) {
+	zip := zipContent(t)
+	entry := bundleEntry(t, "8.7", "gradle-8.7-bin.zip", zip)
+	tampered := entry
+	tampered.SHA256 = strings.Repeat("0", 64)
+
+	// 发行包内含有 ../ 路径时，即使校验和与清单一致也拒绝导入
+	evilZip := filepath.Join(t.TempDir(), "evil.zip")
+	writeZip(t, filepath.Dir(evilZip), filepath.Base(evilZip), "gradle-8.7/lib/gradle.jar", "gradle-8.7/../../../evil.sh")
+	evilData, err := os.ReadFile(evilZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evilEntry := bundleEntry(t, "8.7", "gradle-8.7-bin.zip", string(evilData))
+
+	tests := []struct {
+		name     string
+		manifest BundleManifest
+		files    [][2]string
+		failed   bool // 清单有效但发行包导入失败；否则应在读取清单时拒绝
+	}{
+		{"path in manifest", BundleManifest{Format: 1, Entries: []BundleEntry{{Version: "8.7", Edition: "bin", File: "../gradle-8.7-bin.zip", Size: entry.Size, SHA256: entry.SHA256}}},
+			[][2]string{{"../gradle-8.7-bin.zip", zip}}, false},
+		{"newer format", BundleManifest{Format: bundleFormatVersion + 1, Entries: []BundleEntry{entry}}, nil, false},
+		{"empty manifest", BundleManifest{Format: 1}, nil, false},
+		{"duplicate entry", BundleManifest{Format: 1, Entries: []BundleEntry{entry, entry}}, nil, false},
+		{"unlisted parent path", BundleManifest{Format: 1, Entries: []BundleEntry{entry}},
+			[][2]string{{"../gradle-8.7-bin.zip", zip}, {"../../escape.zip", zip}}, true},
+		{"parent path inside zip", BundleManifest{Format: 1, Entries: []BundleEntry{evilEntry}},
+			[][2]string{{"gradle-8.7-bin.zip", string(evilData)}}, true},
+		{"checksum mismatch", BundleManifest{Format: 1, Entries: []BundleEntry{tampered}},
+			[][2]string{{"gradle-8.7-bin.zip", zip}}, true},
+		{"size mismatch", BundleManifest{Format: 1, Entries: []BundleEntry{entry}},
+			[][2]string{{"gradle-8.7-bin.zip", zip + "x"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := useTempHome(t)
+			// 离线包放在缓存目录的下一级，../ 路径若被写出会落在主目录中
+			bundlePath := filepath.Join(home, "bundle", "gradle.tar")
+			if err := os.MkdirAll(filepath.Dir(bundlePath), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			writeBundle(t, bundlePath, tt.manifest, tt.files...)
+
+			distsDir := filepath.Join(home, "dists")
+			results, err := ImportBundle(context.Background(), bundlePath, distsDir, true, nil)
+			if err == nil {
+				t.Fatalf("results = %+v, want error", results)
+			}
+			if tt.failed != (len(results) > 0) {
+				t.Fatalf("results = %+v, err = %v", results, err)
+			}
+			for _, result := range results {
+				if result.Status != ImportFailed {
+					t.Errorf("%s: status = %s, want %s", result.File, result.Status, ImportFailed)
+				}
+			}
+
+			if cached, err := ListCacheFiles(); err != nil || len(cached) != 0 {
+				t.Errorf("cache files = %v, %v, want none", cached, err)
+			}
+			if _, err := os.Stat(distsDir); !os.IsNotExist(err) {
+				t.Error("dists directory created for a rejected bundle")
+			}
+			// 主目录中除离线包和空缓存目录外不应有其他文件
+			filepath.WalkDir(home, func(path string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && path != bundlePath {
+					t.Errorf("unexpected file %s", path)
+				}
+				return nil
+			})
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 缓存索引文件名，位于缓存目录中
+const cacheIndexFileName = "index.json"
+
+// 缓存索引的读写锁，避免并发下载时互相覆盖
+var cacheIndexMu sync.Mutex
+
+// CacheEntry 缓存索引中单个Gradle发行包的记录
+type CacheEntry struct {
+	Version      string    `json:"version"`
+	Edition      string    `json:"edition"`
+	File         string    `json:"file"`             // 缓存目录中的文件名
+	Mirror       string    `json:"mirror,omitempty"` // 下载所用的镜像源，未知时为空
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+	LastUsedAt   time.Time `json:"last_used_at"`
+}
+
+// 缓存索引文件的内容
+type cacheIndex struct {
+	Entries []CacheEntry `json:"entries"`
+}
+
+// 获取缓存索引文件路径
+func cacheIndexPath() string {
+	return filepath.Join(GetCacheDir(), cacheIndexFileName)
+}
+
+// 查找指定文件的记录，不存在时返回nil
+func (idx *cacheIndex) find(file string) *CacheEntry {
+	for i := range idx.Entries {
+		if idx.Entries[i].File == file {
+			return &idx.Entries[i]
+		}
+	}
+	return nil
+}
+
+// 删除指定文件的记录
+func (idx *cacheIndex) remove(file string) {
+	entries := idx.Entries[:0]
+	for _, entry := range idx.Entries {
+		if entry.File != file {
+			entries = append(entries, entry)
+		}
+	}
+	idx.Entries = entries
+}
+
+// 读取缓存索引，需持有cacheIndexMu
+// 索引不存在时从旧版本的.sha256校验和文件迁移
+func loadCacheIndex() (*cacheIndex, error) {
+	data, err := os.ReadFile(cacheIndexPath())
+	if os.IsNotExist(err) {
+		idx := migrateChecksumFiles()
+		if len(idx.Entries) > 0 {
+			if err := saveCacheIndex(idx); err != nil {
+				return nil, err
+			}
+			// 索引保存成功后才删除旧的校验和文件
+			for _, entry := range idx.Entries {
+				os.Remove(checksumFilePath(filepath.Join(GetCacheDir(), entry.File)))
+			}
+		}
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(T("读取缓存索引失败: %v"), err)
+	}
+
+	var idx cacheIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf(T("缓存索引格式无效: %v"), err)
+	}
+	return &idx, nil
+}
+
+// 保存缓存索引，需持有cacheIndexMu
+func saveCacheIndex(idx *cacheIndex) error {
+	// 按版本号从旧到新排序，便于查看
+	sort.SliceStable(idx.Entries, func(i, j int) bool {
+		a, b := idx.Entries[i], idx.Entries[j]
+		va, errA := ParseGradleVersion(a.Version)
+		vb, errB := ParseGradleVersion(b.Version)
+		if errA == nil && errB == nil && va.Compare(vb) != 0 {
+			return va.Compare(vb) < 0
+		}
+		return a.File < b.File
+	})
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf(T("保存缓存索引失败: %v"), err)
+	}
+	if err := os.MkdirAll(GetCacheDir(), os.ModePerm); err != nil {
+		return fmt.Errorf(T("保存缓存索引失败: %v"), err)
+	}
+	if err := writeFileAtomic(cacheIndexPath(), data, 0644); err != nil {
+		return fmt.Errorf(T("保存缓存索引失败: %v"), err)
+	}
+	return nil
+}
+
+// 读取并修改缓存索引，update返回错误时不保存
+func updateCacheIndex(update func(idx *cacheIndex) error) error {
+	cacheIndexMu.Lock()
+	defer cacheIndexMu.Unlock()
+
+	idx, err := loadCacheIndex()
+	if err != nil {
+		return err
+	}
+	if err := update(idx); err != nil {
+		return err
+	}
+	return saveCacheIndex(idx)
+}
+
+// 将旧版本为每个ZIP文件写入的.sha256校验和文件转换为索引记录
+func migrateChecksumFiles() *cacheIndex {
+	idx := &cacheIndex{}
+	entries, err := os.ReadDir(GetCacheDir())
+	if err != nil {
+		return idx
+	}
+
+	for _, entry := range entries {
+		version, edition, ok := parseCacheFileName(entry.Name())
+		if !ok {
+			continue
+		}
+		zipPath := filepath.Join(GetCacheDir(), entry.Name())
+		data, err := os.ReadFile(checksumFilePath(zipPath))
+		if err != nil {
+			continue
+		}
+		sum := sha256Pattern.FindString(string(data))
+		info, err := entry.Info()
+		if sum == "" || err != nil {
+			continue
+		}
+
+		idx.Entries = append(idx.Entries, CacheEntry{
+			Version:      version,
+			Edition:      edition,
+			File:         entry.Name(),
+			Size:         info.Size(),
+			SHA256:       strings.ToLower(sum),
+			DownloadedAt: info.ModTime(),
+			LastUsedAt:   info.ModTime(),
+		})
+	}
+	return idx
+}
+
+// 从缓存文件名 <版本号>-<版本类型>.zip 中解析版本信息
+func parseCacheFileName(name string) (version, edition string, ok bool) {
+	base, found := strings.CutSuffix(name, ".zip")
+	if !found {
+		return "", "", false
+	}
+	i := strings.LastIndex(base, "-")
+	if i <= 0 {
+		return "", "", false
+	}
+	version, edition = base[:i], base[i+1:]
+	if _, err := ParseGradleVersion(version); err != nil || ValidateEdition(edition) != nil {
+		return "", "", false
+	}
+	return version, edition, true
+}
+
+// 记录新下载的发行包
+func recordCachedGradle(version, edition, mirror, sum string) error {
+	zipPath := CachedGradlePath(version, edition)
+	info, err := os.Stat(zipPath)
+	if err != nil {
+		return fmt.Errorf(T("保存缓存索引失败: %v"), err)
+	}
+
+	now := time.Now()
+	return updateCacheIndex(func(idx *cacheIndex) error {
+		idx.remove(filepath.Base(zipPath))
+		idx.Entries = append(idx.Entries, CacheEntry{
+			Version:      version,
+			Edition:      edition,
+			File:         filepath.Base(zipPath),
+			Mirror:       mirror,
+			Size:         info.Size(),
+			SHA256:       sum,
+			DownloadedAt: now,
+			LastUsedAt:   now,
+		})
+		return nil
+	})
+}
+
+// 更新发行包的最后使用时间
+func touchCachedGradle(version, edition string) error {
+	file := filepath.Base(CachedGradlePath(version, edition))
+	return updateCacheIndex(func(idx *cacheIndex) error {
+		if entry := idx.find(file); entry != nil {
+			entry.LastUsedAt = time.Now()
+		}
+		return nil
+	})
+}
+
+// 从索引中删除发行包的记录
+func forgetCachedGradle(zipPath string) error {
+	return updateCacheIndex(func(idx *cacheIndex) error {
+		idx.remove(filepath.Base(zipPath))
+		return nil
+	})
+}
+
+// 获取指定发行包的索引记录
+func cachedEntry(version, edition string) (*CacheEntry, error) {
+	cacheIndexMu.Lock()
+	defer cacheIndexMu.Unlock()
+
+	idx, err := loadCacheIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.find(filepath.Base(CachedGradlePath(version, edition))), nil
+}
+
+// ListCacheEntries 获取缓存索引中的所有记录，按版本号从旧到新排列
+func ListCacheEntries() ([]CacheEntry, error) {
+	cacheIndexMu.Lock()
+	defer cacheIndexMu.Unlock()
+
+	idx, err := loadCacheIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.Entries, nil
+}
+
+// 缓存校验结果的状态
+const (
+	CacheOK        = "ok"        // 文件完整
+	CacheCorrupt   = "corrupt"   // 文件大小或校验和与记录不一致
+	CacheOrphaned  = "orphaned"  // 索引中有记录但文件已不存在
+	CacheUntracked = "untracked" // 缓存目录中有文件但索引中没有记录
+)
+
+// CacheCheck 单个缓存文件的校验结果
+type CacheCheck struct {
+	File    string `json:"file"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	Removed bool   `json:"removed"` // 是否已删除文件或记录
+}
+
+// 判断缓存目录中的文件是否由程序管理，无需视为多余文件
+// 包括索引本身、用于续传的.partial文件和写入中的临时文件
+func isCacheBookkeepingFile(name string) bool {
+	return name == cacheIndexFileName ||
+		strings.HasSuffix(name, ".partial") ||
+		strings.HasSuffix(name, ".partial.json") ||
+		isAtomicTempFile(name)
+}
+
+// VerifyCache 重新校验缓存索引中的每个发行包，并检查缓存目录中没有记录的文件
+// remove为true时删除损坏和多余的文件，并从索引中移除损坏和丢失的记录
+func VerifyCache(ctx context.Context, remove bool, reporter Reporter) ([]CacheCheck, error) {
+	cacheIndexMu.Lock()
+	defer cacheIndexMu.Unlock()
+
+	idx, err := loadCacheIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []CacheCheck
+	tracked := make(map[string]bool)
+	kept := idx.Entries[:0:0]
+	for _, entry := range idx.Entries {
+		if err := ctx.Err(); err != nil {
+			return checks, err
+		}
+		tracked[entry.File] = true
+		check := CacheCheck{File: entry.File, Status: CacheOK}
+		path := filepath.Join(GetCacheDir(), entry.File)
+
+		reportMessage(reporter, T("正在校验 %s ...\n", entry.File))
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			check.Status = CacheOrphaned
+			check.Detail = err.Error()
+		case info.Size() != entry.Size:
+			check.Status = CacheCorrupt
+			check.Detail = T("文件大小不一致: 期望 %d 字节, 实际 %d 字节", entry.Size, info.Size())
+		default:
+			if err := verifyFileChecksum(path, entry.SHA256); err != nil {
+				check.Status = CacheCorrupt
+				check.Detail = err.Error()
+			}
+		}
+
+		if check.Status != CacheOK && remove {
+			if check.Status == CacheCorrupt {
+				if err := os.Remove(path); err != nil {
+					check.Detail = T("删除文件失败: %s, 错误: %v", path, err)
+					checks = append(checks, check)
+					kept = append(kept, entry)
+					continue
+				}
+				report(reporter, Event{Type: EventFileDeleted, Path: path, Message: T("已删除: %s\n", entry.File)})
+			}
+			check.Removed = true
+		} else {
+			kept = append(kept, entry)
+		}
+		checks = append(checks, check)
+	}
+
+	// 查找没有记录的文件
+	files, err := ListCacheFiles()
+	if err != nil {
+		return checks, err
+	}
+	for _, file := range files {
+		if tracked[file] {
+			continue
+		}
+		check := CacheCheck{File: file, Status: CacheUntracked}
+		if remove {
+			path := filepath.Join(GetCacheDir(), file)
+			if err := os.Remove(path); err != nil {
+				check.Detail = T("删除文件失败: %s, 错误: %v", path, err)
+			} else {
+				report(reporter, Event{Type: EventFileDeleted, Path: path, Message: T("已删除: %s\n", file)})
+				check.Removed = true
+			}
+		}
+		checks = append(checks, check)
+	}
+
+	if remove && len(kept) != len(idx.Entries) {
+		idx.Entries = kept
+		if err := saveCacheIndex(idx); err != nil {
+			return checks, err
+		}
+	}
+	return checks, nil
+}
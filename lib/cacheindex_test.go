@@ -0,0 +1,74 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIsCacheBookkeepingFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"index.json", true},
+		{"gradle-8.7-bin.zip.partial", true},
+		{"gradle-8.7-bin.zip.partial.json", true},
+		{".index.json.tmp-123456", true},
+		{".gradle-8.7-bin.zip.tmp-987654", true},
+		{"gradle-8.7-bin.zip", false},
+		{"gradle-8.7-bin.zip.tmp-1", false},
+		{".DS_Store", false},
+		{"notes.txt", false},
+	}
+	for _, tt := range tests {
+		if got := isCacheBookkeepingFile(tt.name); got != tt.want {
+			t.Errorf("isCacheBookkeepingFile(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestListCacheFilesSkipsBookkeeping(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	cacheDir := GetCacheDir()
+	for _, name := range []string{
+		"gradle-8.7-bin.zip",
+		"notes.txt",
+		cacheIndexFileName,
+		".index.json.tmp-1",
+		filepath.Join(downloadStagingDir, "8.8-bin.zip.partial"),
+	} {
+		path := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"gradle-8.7-bin.zip", "notes.txt"}
+	files, err := ListCacheFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("ListCacheFiles = %v, want %v", files, want)
+	}
+
+	deleted, err := ClearCache(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(deleted)
+	if !slices.Equal(deleted, want) {
+		t.Errorf("ClearCache = %v, want %v", deleted, want)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, cacheIndexFileName)); !os.IsNotExist(err) {
+		t.Errorf("ClearCache kept %s", cacheIndexFileName)
+	}
+}
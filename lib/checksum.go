@@ -0,0 +1,127 @@
+package lib
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Gradle官方发布校验文件地址，镜像源未提供.sha256文件时作为备选
+const officialChecksumURL = "https://services.gradle.org/distributions/gradle-%s-%s.zip.sha256"
+
+// 匹配校验文件中的SHA-256值
+var sha256Pattern = regexp.MustCompile(`(?i)\b[0-9a-f]{64}\b`)
+
+// FileSHA256 计算文件的SHA-256校验和（小写十六进制）
+func FileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// 获取旧版本为缓存ZIP写入的校验和文件路径，现已由缓存索引取代，仅用于迁移
+func checksumFilePath(zipPath string) string {
+	return zipPath + ".sha256"
+}
+
+// 读取单个.sha256文件内容并提取校验和，mirror为所属镜像源，官方地址传零值
+func fetchChecksumFile(ctx context.Context, mirror Mirror, url string) (string, error) {
+	client := newHTTPClient(mirror, 30*time.Second)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	setRequestHeaders(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(T("HTTP状态码错误: %d"), resp.StatusCode)
+	}
+
+	// 校验文件很小，限制读取大小防止异常响应
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256Pattern.FindString(string(body))
+	if sum == "" {
+		return "", fmt.Errorf(T("校验文件格式无效: %s"), url)
+	}
+	return strings.ToLower(sum), nil
+}
+
+// 获取Gradle发行包的官方SHA-256校验和
+// 优先从下载所用镜像获取，失败时回退到Gradle官方地址
+func fetchGradleChecksum(ctx context.Context, mirror Mirror, downloadURL, version, edition string) (string, error) {
+	sum, mirrorErr := fetchChecksumFile(ctx, mirror, downloadURL+".sha256")
+	if mirrorErr == nil {
+		return sum, nil
+	}
+
+	sum, err := fetchChecksumFile(ctx, Mirror{}, fmt.Sprintf(officialChecksumURL, version, edition))
+	if err != nil {
+		return "", fmt.Errorf(T("获取校验和失败: 镜像: %v, 官方: %v"), mirrorErr, err)
+	}
+	return sum, nil
+}
+
+// 校验文件的SHA-256是否与期望值一致
+func verifyFileChecksum(filePath, expected string) error {
+	actual, err := FileSHA256(filePath)
+	if err != nil {
+		return fmt.Errorf(T("计算校验和失败: %v"), err)
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf(T("校验和不匹配: 期望 %s, 实际 %s"), expected, actual)
+	}
+	return nil
+}
+
+// CachedChecksum 读取缓存索引中记录的校验和
+func CachedChecksum(version, edition string) (string, error) {
+	entry, err := cachedEntry(version, edition)
+	if err != nil {
+		return "", err
+	}
+	if entry == nil {
+		return "", fmt.Errorf(T("缺少校验和记录: %s"), filepath.Base(CachedGradlePath(version, edition)))
+	}
+	return entry.SHA256, nil
+}
+
+// VerifyCachedGradle 校验缓存目录中指定Gradle版本的ZIP文件
+// expectedSum非空时（如工作区的distributionSha256Sum），还要求与其一致
+func VerifyCachedGradle(version, edition, expectedSum string) error {
+	recorded, err := CachedChecksum(version, edition)
+	if err != nil {
+		return err
+	}
+	if expectedSum != "" && !strings.EqualFold(recorded, expectedSum) {
+		return fmt.Errorf(T("与distributionSha256Sum不一致: 期望 %s, 缓存 %s"), expectedSum, recorded)
+	}
+
+	return verifyFileChecksum(CachedGradlePath(version, edition), recorded)
+}
@@ -0,0 +1,243 @@
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mirror 镜像源配置
+// URL为下载地址模板，{{version}} 会被替换为Gradle版本号，
+// 可选的 {{edition}} 会被替换为 bin 或 all
+// Proxy为只对该镜像源生效的代理，优先于 --proxy 和全局代理，direct 表示直接连接
+type Mirror struct {
+	Name     string      `yaml:"name"`
+	URL      string      `yaml:"url"`
+	Proxy    string      `yaml:"proxy,omitempty"`
+	Auth     *MirrorAuth `yaml:"auth,omitempty"` // 需要认证的镜像源（如Artifactory、Nexus）
+	Disabled bool        `yaml:"disabled,omitempty"`
+}
+
+// Config 用户配置文件内容
+type Config struct {
+	// 全局代理（http://、https://、socks5:// 地址或 direct），为空时使用 HTTP_PROXY 等环境变量
+	Proxy string `yaml:"proxy,omitempty"`
+
+	// TLS设置，用于使用公司内部CA证书或需要客户端证书的镜像源
+	TLS TLSConfig `yaml:"tls,omitempty"`
+
+	// 镜像源列表，按顺序尝试；为空时使用内置镜像源
+	Mirrors []Mirror `yaml:"mirrors,omitempty"`
+}
+
+// 内置镜像源配置
+var defaultMirrors = []Mirror{
+	{Name: "腾讯镜像-bin", URL: "https://mirrors.cloud.tencent.com/gradle/gradle-{{version}}-bin.zip"},
+	{Name: "腾讯镜像-all", URL: "https://mirrors.cloud.tencent.com/gradle/gradle-{{version}}-all.zip"},
+	{Name: "华为云镜像-bin", URL: "https://mirrors.huaweicloud.com/gradle/gradle-{{version}}-bin.zip"},
+	{Name: "华为云镜像-all", URL: "https://mirrors.huaweicloud.com/gradle/gradle-{{version}}-all.zip"},
+	{Name: "清华镜像-bin", URL: "https://mirrors.tuna.tsinghua.edu.cn/gradle/gradle-{{version}}-bin.zip"},
+	{Name: "清华镜像-all", URL: "https://mirrors.tuna.tsinghua.edu.cn/gradle/gradle-{{version}}-all.zip"},
+}
+
+// 当前生效的镜像源配置
+var mirrors = defaultMirrors
+
+// 是否通过命令行指定了镜像源，指定时按用户给出的顺序尝试，不使用测速排名
+var mirrorsOverridden = false
+
+// 判断镜像源是否提供指定edition的发行包
+func (m Mirror) supportsEdition(edition string) bool {
+	if strings.Contains(m.URL, "{{edition}}") {
+		return true
+	}
+	return strings.HasSuffix(m.URL, "-"+edition+".zip") || strings.HasSuffix(m.Name, "-"+edition)
+}
+
+// 生成指定版本的下载地址
+func (m Mirror) distributionURL(version, edition string) string {
+	url := strings.ReplaceAll(m.URL, "{{version}}", version)
+	return strings.ReplaceAll(url, "{{edition}}", edition)
+}
+
+// 获取工具数据目录（缓存、配置等均存放于此）
+func getAppDir() string {
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		// 如果获取用户目录失败，使用当前目录作为备选方案
+		return "."
+	}
+	return filepath.Join(userHome, ".mcrgradletool")
+}
+
+// GetConfigPath 获取配置文件路径
+func GetConfigPath() string {
+	return filepath.Join(getAppDir(), "config.yaml")
+}
+
+// LoadConfig 读取配置文件，文件不存在时返回空配置
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(GetConfigPath())
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(T("读取配置文件失败: %v"), err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf(T("解析配置文件失败: %s: %v"), GetConfigPath(), err)
+	}
+	if err := validateMirrors(cfg.Mirrors); err != nil {
+		return nil, fmt.Errorf(T("配置文件无效: %s: %v"), GetConfigPath(), err)
+	}
+	if err := validateProxy(cfg.Proxy); err != nil {
+		return nil, fmt.Errorf(T("配置文件无效: %s: %v"), GetConfigPath(), err)
+	}
+
+	// 证书的相对路径相对于配置文件所在目录
+	configDir := filepath.Dir(GetConfigPath())
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(configDir, path)
+	}
+	for i, path := range cfg.TLS.CACerts {
+		cfg.TLS.CACerts[i] = resolve(path)
+	}
+	cfg.TLS.ClientCert = resolve(cfg.TLS.ClientCert)
+	cfg.TLS.ClientKey = resolve(cfg.TLS.ClientKey)
+	return cfg, nil
+}
+
+// 检查镜像源配置是否有效
+func validateMirrors(list []Mirror) error {
+	names := make(map[string]bool)
+	for i, mirror := range list {
+		if mirror.Name == "" {
+			return fmt.Errorf(T("第%d个镜像源缺少name"), i+1)
+		}
+		if names[mirror.Name] {
+			return fmt.Errorf(T("镜像源名称重复: %s"), mirror.Name)
+		}
+		names[mirror.Name] = true
+
+		if !strings.Contains(mirror.URL, "{{version}}") {
+			return fmt.Errorf(T("镜像源 %s 的url缺少 {{version}} 占位符"), mirror.Name)
+		}
+		if !strings.HasPrefix(mirror.URL, "http://") && !strings.HasPrefix(mirror.URL, "https://") {
+			return fmt.Errorf(T("镜像源 %s 的url必须以 http:// 或 https:// 开头"), mirror.Name)
+		}
+		if err := validateProxy(mirror.Proxy); err != nil {
+			return fmt.Errorf(T("镜像源 %s 的proxy无效: %v"), mirror.Name, err)
+		}
+		if mirror.Auth != nil {
+			if err := mirror.Auth.validate(); err != nil {
+				return fmt.Errorf(T("镜像源 %s 的auth无效: %v"), mirror.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyConfig 应用配置文件中的设置
+func ApplyConfig(cfg *Config) {
+	configProxy = cfg.Proxy
+	if len(cfg.Mirrors) > 0 {
+		mirrors = cfg.Mirrors
+	} else {
+		mirrors = defaultMirrors
+	}
+}
+
+// InitConfig 将内置镜像源写入配置文件，作为用户自定义的起点
+func InitConfig(overwrite bool) (string, error) {
+	configPath := GetConfigPath()
+	if _, err := os.Stat(configPath); err == nil && !overwrite {
+		return configPath, fmt.Errorf(T("配置文件已存在: %s"), configPath)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(T("# MCr_gradletools 配置文件\n") +
+		T("# 镜像源按顺序尝试，可增删、调整顺序，或设置 disabled: true 临时停用\n") +
+		T("# url中的 {{version}} 会被替换为Gradle版本号，{{edition}} 会被替换为 bin 或 all\n") +
+		T("# 需要代理时可设置 proxy: http://127.0.0.1:7890（也支持 socks5://），\n") +
+		T("# 镜像源中的 proxy 只对该镜像生效，direct 表示直接连接\n"))
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&Config{Mirrors: defaultMirrors}); err != nil {
+		return configPath, fmt.Errorf(T("生成配置文件失败: %v"), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), os.ModePerm); err != nil {
+		return configPath, fmt.Errorf(T("创建配置目录失败: %v"), err)
+	}
+	if err := writeFileAtomic(configPath, buf.Bytes(), 0644); err != nil {
+		return configPath, fmt.Errorf(T("写入配置文件失败: %v"), err)
+	}
+	return configPath, nil
+}
+
+// GetMirrors 获取当前生效的镜像源列表（包括已停用的）
+func GetMirrors() []Mirror {
+	return mirrors
+}
+
+// 获取当前启用的镜像源
+func enabledMirrors() []Mirror {
+	var result []Mirror
+	for _, mirror := range mirrors {
+		if !mirror.Disabled {
+			result = append(result, mirror)
+		}
+	}
+	return result
+}
+
+// UseMirrors 使用命令行指定的镜像源覆盖配置
+// 每一项可以是已配置镜像源的名称（即使已停用），也可以是包含 {{version}} 的URL模板
+func UseMirrors(specs []string) error {
+	var selected []Mirror
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		found := false
+		for _, mirror := range mirrors {
+			if mirror.Name == spec {
+				mirror.Disabled = false
+				selected = append(selected, mirror)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		if !strings.Contains(spec, "://") {
+			return fmt.Errorf(T("未知的镜像源: %s"), spec)
+		}
+		// URL中可能包含密码，名称会显示在输出中
+		selected = append(selected, Mirror{Name: RedactURL(spec), URL: spec})
+	}
+
+	if err := validateMirrors(selected); err != nil {
+		return err
+	}
+	if len(selected) > 0 {
+		mirrors = selected
+		mirrorsOverridden = true
+	}
+	return nil
+}
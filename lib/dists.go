@@ -0,0 +1,231 @@
+package lib
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Wrapper dists目录中发行包的状态
+const (
+	DistOK         = "ok"         // 已解压安装（存在.ok标记和解压目录）
+	DistIncomplete = "incomplete" // 尚未安装完成（缺少.ok标记）
+	DistLocked     = "locked"     // 未安装完成且临时文件正被MCreator或Gradle进程使用，可能正在下载
+	DistCorrupt    = "corrupt"    // 有.ok标记但解压目录无效，或ZIP文件已损坏
+)
+
+// DistEntry dists目录中的一个发行包（dists/gradle-X-bin/<哈希> 目录）
+type DistEntry struct {
+	Version string `json:"version"`
+	Edition string `json:"edition"`
+	Dir     string `json:"dir"`
+	State   string `json:"state"`
+	Size    int64  `json:"size"`
+	Detail  string `json:"detail,omitempty"`
+
+	// ZIP文件完整，只是尚未解压（Wrapper首次使用时会自行解压）
+	NeedsUnpack bool `json:"needs_unpack,omitempty"`
+}
+
+// 获取发行包目录中的ZIP文件路径
+func (e DistEntry) zipPath() string {
+	return filepath.Join(e.Dir, fmt.Sprintf("gradle-%s-%s.zip", e.Version, e.Edition))
+}
+
+// ListDists 列出Wrapper dists目录中的所有发行包及其状态，按版本号从旧到新排列
+func ListDists(distsDir string) ([]DistEntry, error) {
+	if _, err := os.Stat(distsDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf(T("gradle目录不存在: %s"), distsDir)
+	}
+
+	distDirs, err := os.ReadDir(distsDir)
+	if err != nil {
+		return nil, fmt.Errorf(T("扫描Gradle目录失败: %v"), err)
+	}
+
+	var entries []DistEntry
+	for _, distDir := range distDirs {
+		if !distDir.IsDir() {
+			continue
+		}
+		version, edition, err := extractGradleVersion(distDir.Name() + ".zip")
+		if err != nil {
+			continue
+		}
+
+		hashDirs, err := os.ReadDir(filepath.Join(distsDir, distDir.Name()))
+		if err != nil {
+			return nil, fmt.Errorf(T("扫描Gradle目录失败: %v"), err)
+		}
+		for _, hashDir := range hashDirs {
+			if !hashDir.IsDir() {
+				continue
+			}
+			entry := DistEntry{
+				Version: version,
+				Edition: edition,
+				Dir:     filepath.Join(distsDir, distDir.Name(), hashDir.Name()),
+			}
+			entry.State, entry.Detail, entry.NeedsUnpack = distState(entry)
+			entry.Size = dirSize(entry.Dir)
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		vi, _ := ParseGradleVersion(entries[i].Version)
+		vj, _ := ParseGradleVersion(entries[j].Version)
+		return vi.Compare(vj) < 0
+	})
+	return entries, nil
+}
+
+// 判断发行包的安装状态，返回状态、说明以及是否只需解压
+func distState(entry DistEntry) (state, detail string, needsUnpack bool) {
+	zipPath := entry.zipPath()
+
+	if _, err := os.Stat(wrapperMarkerPath(zipPath)); err == nil {
+		// Wrapper认为已安装，检查解压目录是否完整
+		rootName := "gradle-" + entry.Version
+		if _, err := os.Stat(filepath.Join(entry.Dir, rootName, "lib")); err != nil {
+			return DistCorrupt, T("发行包结构无效，缺少 %s/lib 目录", rootName), false
+		}
+		return DistOK, "", false
+	}
+
+	// 只有.lck文件正被进程使用时才视为锁定，遗留的.lck文件不影响判断
+	// 无法检测占用的平台上，存在.lck文件即视为锁定
+	info := GradleFileInfo{Version: entry.Version, Edition: entry.Edition}
+	if _, err := os.Stat(wrapperLockPath(zipPath)); err == nil {
+		info.LockFile = wrapperLockPath(zipPath)
+	}
+	if _, err := os.Stat(zipPath + ".part"); err == nil {
+		info.PartFile = zipPath + ".part"
+	}
+	if info.LockFile != "" || info.PartFile != "" {
+		holders, err := GradleLockHolders(info)
+		switch {
+		case err != nil && info.LockFile != "":
+			return DistLocked, T("存在.lck锁文件"), false
+		case len(holders) > 0:
+			return DistLocked, T("正在被 %s 使用", describeLockHolders(holders)), false
+		}
+	}
+
+	if _, err := os.Stat(zipPath); err == nil {
+		reader, err := zip.OpenReader(zipPath)
+		if err != nil {
+			return DistCorrupt, T("ZIP文件无效: %v", err), false
+		}
+		reader.Close()
+		return DistIncomplete, T("发行包未解压"), true
+	}
+	if _, err := os.Stat(zipPath + ".part"); err == nil {
+		return DistIncomplete, T("下载未完成"), false
+	}
+	return DistIncomplete, T("缺少发行包"), false
+}
+
+// 计算目录中所有文件的总大小
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// UnusedDists 找出没有被任何工作区使用的发行包
+// 工作区使用的发行包按Wrapper安装目录精确匹配；锁定中的发行包可能正在下载，不会被选中
+func UnusedDists(distsDir string, workspaceDirs []string) ([]DistEntry, error) {
+	entries, err := ListDists(distsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, dir := range workspaceDirs {
+		status := CheckWorkspace(distsDir, dir)
+		if status.Err == nil {
+			used[filepath.Clean(status.InstallDir)] = true
+		}
+	}
+
+	var unused []DistEntry
+	for _, entry := range entries {
+		if used[filepath.Clean(entry.Dir)] || entry.State == DistLocked {
+			continue
+		}
+		unused = append(unused, entry)
+	}
+	return unused, nil
+}
+
+// RemoveDists 删除发行包目录，所在的 gradle-X-bin 目录为空时一并删除，返回已删除的目录
+func RemoveDists(entries []DistEntry, reporter Reporter) ([]string, error) {
+	var removed []string
+	for _, entry := range entries {
+		if err := os.RemoveAll(entry.Dir); err != nil {
+			return removed, fmt.Errorf(T("删除文件失败: %s, 错误: %v"), entry.Dir, err)
+		}
+		report(reporter, Event{Type: EventFileDeleted, Path: entry.Dir,
+			Message: T("已删除: %s\n", filepath.Join(filepath.Base(filepath.Dir(entry.Dir)), filepath.Base(entry.Dir)))})
+		removed = append(removed, entry.Dir)
+
+		// os.Remove只能删除空目录
+		os.Remove(filepath.Dir(entry.Dir))
+	}
+	return removed, nil
+}
+
+// RepairDists 重新下载、复制（opts.Unpack为true时再解压）状态异常的发行包
+// workspaceDirs用于读取distributionSha256Sum；最多同时修复opts.Jobs个，单个发行包失败不影响其他发行包
+func RepairDists(ctx context.Context, distsDir string, entries []DistEntry, workspaceDirs []string, opts ProcessOptions, reporter Reporter) []GradleResult {
+	files := make([]GradleFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info := GradleFileInfo{Version: entry.Version, Edition: entry.Edition, TargetDir: entry.Dir}
+		zipPath := entry.zipPath()
+		if _, err := os.Stat(wrapperLockPath(zipPath)); err == nil {
+			info.LockFile = wrapperLockPath(zipPath)
+		}
+		if _, err := os.Stat(zipPath + ".part"); err == nil {
+			info.PartFile = zipPath + ".part"
+		}
+		files = append(files, info)
+	}
+	applyWorkspaceChecksums(distsDir, files, workspaceDirs)
+
+	results := processGradleFiles(ctx, files, opts.Jobs, reporter, func(ctx context.Context, i int, fileInfo GradleFileInfo, reporter Reporter) error {
+		reportMessage(reporter, T("\n[%d/%d] 修复Gradle %s %s版:\n",
+			i+1, len(files), fileInfo.Version, fileInfo.Edition))
+
+		// 删除失效的.ok标记，否则Wrapper会认为已安装而不再解压
+		err := os.Remove(wrapperMarkerPath(entries[i].zipPath()))
+		if err == nil || os.IsNotExist(err) {
+			err = processGradleVersion(ctx, fileInfo, opts, reporter)
+		}
+		if err != nil {
+			reportMessage(reporter, T("❌ Gradle %s %s版修复失败: %v\n", fileInfo.Version, fileInfo.Edition, err))
+			return err
+		}
+		reportMessage(reporter, T("✅ Gradle %s %s版修复完成\n", fileInfo.Version, fileInfo.Edition))
+		return nil
+	})
+
+	if len(files) > 1 {
+		reportGradleSummary(reporter, results)
+	}
+	return results
+}
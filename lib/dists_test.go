@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 在dists目录中创建 gradle-<version>-bin/<hash> 目录并按files写入文件
+func makeDist(t *testing.T, distsDir, version string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(distsDir, "gradle-"+version+"-bin", "hash")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// 生成只含一个文件的有效ZIP内容
+func zipContent(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.zip")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(file)
+	if _, err := w.Create("gradle-8.7/lib/gradle.jar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestListDistsState(t *testing.T) {
+	distsDir := t.TempDir()
+	makeDist(t, distsDir, "8.3", map[string]string{"gradle-8.3-bin.zip.ok": "", "gradle-8.3/lib/gradle.jar": ""})
+	makeDist(t, distsDir, "8.4", map[string]string{"gradle-8.4-bin.zip.ok": ""})
+	makeDist(t, distsDir, "8.5", map[string]string{"gradle-8.5-bin.zip": "not a zip"})
+	makeDist(t, distsDir, "8.6", map[string]string{"gradle-8.6-bin.zip.part": "partial"})
+	makeDist(t, distsDir, "8.7", map[string]string{"gradle-8.7-bin.zip": zipContent(t)})
+	makeDist(t, distsDir, "8.8", nil)
+
+	tests := []struct {
+		version     string
+		state       string
+		needsUnpack bool
+	}{
+		{"8.3", DistOK, false},
+		{"8.4", DistCorrupt, false},
+		{"8.5", DistCorrupt, false},
+		{"8.6", DistIncomplete, false},
+		{"8.7", DistIncomplete, true},
+		{"8.8", DistIncomplete, false},
+	}
+
+	// 是否只需解压不应依赖说明文字的语言
+	defer SetLocale(GetLocale())
+	for _, lang := range []string{LocaleZhCN, LocaleEn} {
+		if err := SetLocale(lang); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := ListDists(distsDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != len(tests) {
+			t.Fatalf("%s: ListDists returned %d entries, want %d", lang, len(entries), len(tests))
+		}
+		for i, tt := range tests {
+			entry := entries[i]
+			if entry.Version != tt.version || entry.State != tt.state || entry.NeedsUnpack != tt.needsUnpack {
+				t.Errorf("%s: entry %d = %s %s needs_unpack=%v, want %s %s needs_unpack=%v",
+					lang, i, entry.Version, entry.State, entry.NeedsUnpack, tt.version, tt.state, tt.needsUnpack)
+			}
+		}
+	}
+}
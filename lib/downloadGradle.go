@@ -1,166 +1,212 @@
 package lib
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/schollz/progressbar/v3"
 )
 
-// 镜像源配置
-var mirrors = []struct {
-	name string
-	url  string
-}{
-	{"腾讯镜像-bin", "https://mirrors.cloud.tencent.com/gradle/gradle-{{version}}-bin.zip"},
-	{"腾讯镜像-all", "https://mirrors.cloud.tencent.com/gradle/gradle-{{version}}-all.zip"},
-	{"华为云镜像-bin", "https://mirrors.huaweicloud.com/gradle/gradle-{{version}}-bin.zip"},
-	{"华为云镜像-all", "https://mirrors.huaweicloud.com/gradle/gradle-{{version}}-all.zip"},
-	{"清华镜像-bin", "https://mirrors.tuna.tsinghua.edu.cn/gradle/gradle-{{version}}-bin.zip"},
-	{"清华镜像-all", "https://mirrors.tuna.tsinghua.edu.cn/gradle/gradle-{{version}}-all.zip"},
+// 单次下载请求的超时时间，超时后由重试策略从已下载位置断点续传
+const downloadAttemptTimeout = 300 * time.Second
+
+// HTTP状态码错误
+type httpStatusError struct {
+	code int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf(T("HTTP状态码错误: %d"), e.code)
+}
+
+// 下载的文件大小与探测结果不一致，说明镜像的HEAD响应与实际内容不符，在同一镜像上重试没有意义
+type sizeMismatchError struct {
+	expected int64
+	actual   int64
 }
 
-// 检查镜像是否可用
-func checkMirrorAvailability(url string) bool {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+func (e *sizeMismatchError) Error() string {
+	return fmt.Sprintf(T("文件大小不一致: 期望 %d 字节, 实际 %d 字节"), e.expected, e.actual)
+}
+
+// 获取下载过程中使用的临时文件路径
+func partialFilePath(destPath string) string {
+	return destPath + ".partial"
+}
+
+// 下载文件（单次尝试），返回本次写入的字节数
+// 数据先写入.partial文件，已有部分时使用Range请求续传，完整后才重命名为目标文件
+// probe为下载前探测到的文件信息，用于进度显示、续传判断和完整性校验
+func downloadFile(ctx context.Context, client *http.Client, url, destPath string, probe *probeResult, reporter Reporter) (int64, error) {
+	partialPath := partialFilePath(destPath)
+
+	var offset int64
+	if info, err := os.Stat(partialPath); err == nil {
+		offset = info.Size()
 	}
 
-	// 使用简单的GET请求检查镜像可用性
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return false
+	// 已下载部分无法续传时从头开始
+	meta := loadPartialMeta(partialPath)
+	if offset > 0 && !canResume(meta, offset, probe) {
+		reportMessage(reporter, T("已下载部分无法在当前镜像续传，重新开始下载"))
+		os.Remove(partialPath)
+		offset = 0
+		meta = nil
 	}
 
-	// 添加常见的HTTP请求头，模拟浏览器行为
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+	// 同一地址续传时使用If-Range，服务器文件变化则返回完整内容
+	ifRange := ""
+	if meta != nil && meta.URL == RedactURL(url) && meta.ETag == probe.ETag {
+		ifRange = probe.ETag
+	}
+	if err := savePartialMeta(partialPath, partialMeta{URL: RedactURL(url), ETag: probe.ETag, Size: probe.ContentLength}); err != nil {
+		return 0, fmt.Errorf(T("写入下载记录失败: %w"), err)
+	}
 
-	resp, err := client.Do(req)
+	written, err := downloadRange(ctx, client, url, partialPath, offset, probe.ContentLength, ifRange, reporter)
 	if err != nil {
-		return false
+		return written, err
 	}
-	defer resp.Body.Close()
 
-	// 接受200 OK状态码
-	return resp.StatusCode == http.StatusOK
-}
+	// 核对文件大小与探测结果一致
+	if probe.ContentLength >= 0 {
+		info, err := os.Stat(partialPath)
+		if err != nil {
+			return written, fmt.Errorf(T("读取下载文件失败: %w"), err)
+		}
+		if info.Size() != probe.ContentLength {
+			// 已下载的数据被丢弃，不算作进展
+			os.Remove(partialPath)
+			return 0, &sizeMismatchError{expected: probe.ContentLength, actual: info.Size()}
+		}
+	}
 
-// 下载文件
-func downloadFile(url, filepath string) error {
-	client := &http.Client{
-		Timeout: 300 * time.Second, // 下载需要更长的超时时间（5分钟）
+	// 下载完整后再重命名为最终文件名
+	if err := os.Rename(partialPath, destPath); err != nil {
+		return written, fmt.Errorf(T("重命名下载文件失败: %w"), err)
 	}
+	os.Remove(partialMetaPath(partialPath))
+	return written, nil
+}
 
+// 从指定偏移量开始下载，返回本次写入的字节数
+// knownSize为探测得到的文件大小（未知时为-1），ifRange非空时作为If-Range头发送
+func downloadRange(ctx context.Context, client *http.Client, url, partialPath string, offset, knownSize int64, ifRange string, reporter Reporter) (int64, error) {
 	// 创建请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("创建下载请求失败: %v", err)
+		return 0, fmt.Errorf(T("创建下载请求失败: %v"), err)
 	}
 
-	// 添加常见的HTTP请求头，模拟浏览器行为
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+	setRequestHeaders(req)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if ifRange != "" {
+			req.Header.Set("If-Range", ifRange)
+		}
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("下载失败: %v", err)
+		return 0, fmt.Errorf(T("下载失败: %w"), err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP状态码错误: %d", resp.StatusCode)
+	var total int64
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		// 服务器支持续传，追加写入
+		flags |= os.O_APPEND
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		} else {
+			total = knownSize
+		}
+		reportMessage(reporter, T("从 %d 字节处继续下载\n", offset))
+	case resp.StatusCode == http.StatusOK:
+		// 服务器不支持Range或首次下载，从头开始
+		flags |= os.O_TRUNC
+		if offset > 0 {
+			reportMessage(reporter, T("服务器不支持断点续传，重新开始下载"))
+		}
+		offset = 0
+		total = resp.ContentLength
+		if total < 0 {
+			total = knownSize
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// 已下载的部分可能已经完整，否则丢弃后重新下载
+		if size, ok := parseContentRangeTotal(resp.Header.Get("Content-Range")); ok && size == offset {
+			return 0, nil
+		}
+		os.Remove(partialPath)
+		return 0, errors.New(T("已下载部分与服务器文件不一致，重新下载"))
+	default:
+		return 0, &httpStatusError{code: resp.StatusCode}
 	}
 
-	// 获取文件大小用于进度条
-	contentLength := resp.ContentLength
-
-	out, err := os.Create(filepath)
+	out, err := os.OpenFile(partialPath, flags, 0644)
 	if err != nil {
-		return fmt.Errorf("创建文件失败: %v", err)
+		return 0, fmt.Errorf(T("创建文件失败: %w"), err)
 	}
 	defer out.Close()
 
-	// 创建进度条
-	bar := progressbar.NewOptions64(
-		contentLength,
-		progressbar.OptionSetDescription("📥 下载进度"),
-		progressbar.OptionSetWriter(os.Stderr),
-		progressbar.OptionShowBytes(true),
-		progressbar.OptionSetWidth(60),
-		progressbar.OptionThrottle(50*time.Millisecond),
-		progressbar.OptionShowCount(),
-		progressbar.OptionOnCompletion(func() {
-			fmt.Fprint(os.Stderr, "✅ 下载完成\n")
-		}),
-		progressbar.OptionSpinnerType(9),
-		progressbar.OptionFullWidth(),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "█",
-			SaucerHead:    "🟢",
-			SaucerPadding: "░",
-			BarStart:      "|",
-			BarEnd:        "|",
-		}),
-	)
-
-	// 使用带进度条的下载
-	_, err = io.Copy(io.MultiWriter(out, bar), resp.Body)
+	// 通知开始下载，进度从已下载位置开始计算
+	report(reporter, Event{Type: EventStarted, Task: TaskDownload, URL: RedactURL(url), Path: partialPath, Current: offset, Total: total})
+	progress := newProgressWriter(reporter, TaskDownload, partialPath, offset, total)
+
+	written, err := io.Copy(io.MultiWriter(out, progress), resp.Body)
+
+	// 连接提前关闭时io.Copy不会报错，需要核对长度
+	if err == nil && total >= 0 && offset+written < total {
+		err = io.ErrUnexpectedEOF
+	}
 	if err != nil {
-		return fmt.Errorf("下载失败: %v", err)
+		report(reporter, Event{Type: EventDone, Task: TaskDownload, URL: RedactURL(url), Path: partialPath, Error: err.Error()})
+		return written, err
 	}
 
-	return nil
+	report(reporter, Event{Type: EventDone, Task: TaskDownload, URL: RedactURL(url), Path: partialPath, Current: offset + written, Total: total})
+	return written, nil
 }
 
-// 检查所有镜像源的可用性
-func CheckAllMirrors() ([]string, []string) {
-	var availableMirrors []string
-	var unavailableMirrors []string
-
-	// 使用一个测试版本号来检查镜像可用性
-	testVersion := "8.7"
-
-	for _, mirror := range mirrors {
-		// 替换版本号占位符
-		url := strings.Replace(mirror.url, "{{version}}", testVersion, -1)
-
-		if checkMirrorAvailability(url) {
-			availableMirrors = append(availableMirrors, mirror.name)
-		} else {
-			unavailableMirrors = append(unavailableMirrors, mirror.name)
-		}
+// 解析 "bytes */12345" 形式的Content-Range头，获取文件总大小
+func parseContentRangeTotal(contentRange string) (int64, bool) {
+	idx := strings.LastIndex(contentRange, "/")
+	if idx < 0 {
+		return 0, false
 	}
-
-	return availableMirrors, unavailableMirrors
+	size, err := strconv.ParseInt(strings.TrimSpace(contentRange[idx+1:]), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return size, true
 }
 
 // 获取缓存目录路径
 func GetCacheDir() string {
 	// 使用用户的应用数据目录，避免写入桌面
-	userHome, err := os.UserHomeDir()
-	if err != nil {
-		// 如果获取用户目录失败，使用当前目录作为备选方案
-		return filepath.Join(".", "cache")
-	}
-	// 在用户主目录下创建 .mcrgradletool 目录存放缓存
-	return filepath.Join(userHome, ".mcrgradletool", "cache")
+	// 在用户主目录下的 .mcrgradletool 目录存放缓存
+	return filepath.Join(getAppDir(), "cache")
 }
 
-// 删除缓存目录中的所有文件
-func ClearCache() error {
+// 删除缓存目录中的所有文件，返回已删除的文件名
+// 索引、.partial等由程序管理的文件同样删除，但不计入返回的文件名
+func ClearCache(reporter Reporter) ([]string, error) {
 	cacheDir := GetCacheDir()
+	var deleted []string
 
 	// 检查缓存目录是否存在
 	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
-		return fmt.Errorf("缓存目录不存在: %s", cacheDir)
+		return nil, fmt.Errorf(T("缓存目录不存在: %s"), cacheDir)
 	}
 
 	// 遍历缓存目录并删除所有文件
@@ -172,22 +218,26 @@ func ClearCache() error {
 		// 跳过目录本身，只删除文件
 		if !info.IsDir() {
 			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("删除文件失败: %s, 错误: %v", path, err)
+				return fmt.Errorf(T("删除文件失败: %s, 错误: %v"), path, err)
+			}
+			if isCacheBookkeepingFile(info.Name()) {
+				return nil
 			}
-			fmt.Printf("已删除: %s\n", filepath.Base(path))
+			report(reporter, Event{Type: EventFileDeleted, Path: path, Message: T("已删除: %s\n", filepath.Base(path))})
+			deleted = append(deleted, filepath.Base(path))
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return fmt.Errorf("清理缓存失败: %v", err)
+		return deleted, fmt.Errorf(T("清理缓存失败: %v"), err)
 	}
 
-	return nil
+	return deleted, nil
 }
 
-// 获取缓存目录中的文件列表
+// 获取缓存目录中的文件列表，不包括索引、.partial等由程序管理的文件
 func ListCacheFiles() ([]string, error) {
 	cacheDir := GetCacheDir()
 	var files []string
@@ -200,11 +250,11 @@ func ListCacheFiles() ([]string, error) {
 	// 读取缓存目录
 	entries, err := os.ReadDir(cacheDir)
 	if err != nil {
-		return nil, fmt.Errorf("读取缓存目录失败: %v", err)
+		return nil, fmt.Errorf(T("读取缓存目录失败: %v"), err)
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() && !isCacheBookkeepingFile(entry.Name()) {
 			files = append(files, entry.Name())
 		}
 	}
@@ -212,57 +262,177 @@ func ListCacheFiles() ([]string, error) {
 	return files, nil
 }
 
+// CachedGradlePath 获取缓存目录中指定Gradle版本的ZIP文件路径
+func CachedGradlePath(version, edition string) string {
+	return filepath.Join(GetCacheDir(), version+"-"+edition+".zip")
+}
+
+// 下载中的发行包所在的子目录，校验通过后才移入缓存目录，下载失败时不影响已有的缓存文件
+const downloadStagingDir = "downloads"
+
+// 获取下载指定Gradle版本时使用的临时路径
+func stagingGradlePath(version, edition string) string {
+	return filepath.Join(GetCacheDir(), downloadStagingDir, version+"-"+edition+".zip")
+}
+
+// 每个缓存文件的下载锁，并发处理时同一发行包只下载一次
+var (
+	downloadLocksMu sync.Mutex
+	downloadLocks   = make(map[string]*sync.Mutex)
+)
+
+// 锁定缓存中的指定发行包，返回解锁函数
+func lockCachedGradle(version, edition string) func() {
+	path := CachedGradlePath(version, edition)
+	downloadLocksMu.Lock()
+	lock, ok := downloadLocks[path]
+	if !ok {
+		lock = &sync.Mutex{}
+		downloadLocks[path] = lock
+	}
+	downloadLocksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// 删除缓存中的ZIP文件及其索引记录
+func removeCachedGradle(zipPath string) {
+	os.Remove(zipPath)
+	forgetCachedGradle(zipPath)
+}
+
 // 下载并安装Gradle
 // edition参数指定下载版本："bin" 或 "all"
-func DownloadGradle(version, edition string) error {
+// expectedSum为工作区gradle-wrapper.properties中的distributionSha256Sum，未设置时传空字符串；
+// 设置时以其为准校验，缓存或下载的文件与其不一致则换镜像重新下载
+// 新文件先下载到 downloads 子目录，校验通过后才替换缓存文件；所有镜像都失败时缓存保持不变
+// 下载过程中的事件发送给reporter；ctx取消时立即停止，已下载的.partial文件保留以便下次续传
+// 离线模式下只使用缓存，缓存中没有或校验失败时返回错误
+// 返回已校验的缓存文件路径
+func DownloadGradle(ctx context.Context, version, edition, expectedSum string, reporter Reporter) (string, error) {
+	// 其他任务正在下载同一发行包时等待其完成，之后直接使用缓存
+	unlock := lockCachedGradle(version, edition)
+	defer unlock()
+
+	return downloadCachedGradle(ctx, version, edition, expectedSum, reporter)
+}
+
+// DownloadGradle的实现，调用方需持有该版本的缓存锁
+// 返回后到释放锁之前，缓存文件不会被其他任务替换，可直接使用而无需再次校验
+func downloadCachedGradle(ctx context.Context, version, edition, expectedSum string, reporter Reporter) (string, error) {
+	// 检查参数有效性
+	if _, err := ParseGradleVersion(version); err != nil {
+		return "", err
+	}
+	if err := ValidateEdition(edition); err != nil {
+		return "", err
+	}
 
 	// 创建缓存目录
-	cacheDir := GetCacheDir()
-	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
-		return fmt.Errorf("创建缓存目录失败: %v", err)
+	if err := os.MkdirAll(filepath.Join(GetCacheDir(), downloadStagingDir), os.ModePerm); err != nil {
+		return "", fmt.Errorf(T("创建缓存目录失败: %v"), err)
 	}
 
-	// 检查是否已存在（检查ZIP文件，区分edition）
-	gradleZipFile := filepath.Join(cacheDir, version+"-"+edition+".zip")
+	// 检查是否已存在（检查ZIP文件，区分edition），并确认校验和一致
+	gradleZipFile := CachedGradlePath(version, edition)
 	if _, err := os.Stat(gradleZipFile); err == nil {
-		fmt.Printf("Gradle %s %s版 已存在于缓存目录中\n", version, edition)
-		return nil
+		err := VerifyCachedGradle(version, edition, expectedSum)
+		if err == nil {
+			reportMessage(reporter, T("Gradle %s %s版 已存在于缓存目录中\n", version, edition))
+			touchCachedGradle(version, edition)
+			return gradleZipFile, nil
+		}
+		if offline {
+			return "", fmt.Errorf(T("离线模式下无法重新下载，缓存中的Gradle %s %s版 校验失败: %v"), version, edition, err)
+		}
+		// 新文件下载并校验成功后才替换，在此之前保留原文件和索引记录
+		reportMessage(reporter, T("缓存中的Gradle %s %s版 校验失败，将重新下载: %v\n", version, edition, err))
 	}
-
-	// 检查edition参数有效性
-	if edition != "bin" && edition != "all" {
-		return fmt.Errorf("edition参数必须为 'bin' 或 'all'，当前为: %s", edition)
+	if offline {
+		return "", fmt.Errorf(T("离线模式: 缓存中没有Gradle %s %s版，请先使用 import 命令导入"), version, edition)
 	}
 
-	// 尝试不同的镜像源（根据edition过滤）
-	var availableMirror string
-	for _, mirror := range mirrors {
+	stagingFile := stagingGradlePath(version, edition)
+
+	// 依次尝试不同的镜像源（根据edition过滤，按上次测速排名优先），
+	// 每个镜像内对临时错误退避重试，失败后换下一个
+	var failures []mirrorFailure
+	for _, mirror := range rankedMirrors(enabledMirrors()) {
 		// 只检查与指定edition匹配的镜像源
-		if strings.HasSuffix(mirror.name, "-"+edition) {
-			url := strings.Replace(mirror.url, "{{version}}", version, -1)
-			fmt.Printf("正在检查 %s 可用性...\n", mirror.name)
-
-			if checkMirrorAvailability(url) {
-				fmt.Printf("%s 可用\n", mirror.name)
-				availableMirror = url
-				break
+		if !mirror.supportsEdition(edition) {
+			continue
+		}
+
+		url := mirror.distributionURL(version, edition)
+		reportMessage(reporter, T("正在检查 %s 可用性...\n", mirror.Name))
+		if proxy := describeProxy(mirror.Proxy, url); proxy != "" {
+			reportMessage(reporter, T("通过代理 %s 连接\n", proxy))
+		}
+
+		probe, err := probeMirror(ctx, mirror, url)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err != nil {
+			err = explainAuthError(mirror, explainTLSError(err))
+			report(reporter, Event{Type: EventMirrorTried, Mirror: mirror.Name, URL: RedactURL(url), Error: err.Error(),
+				Message: T("%s 不可用: %v\n", mirror.Name, err)})
+			failures = append(failures, mirrorFailure{mirror: mirror.Name, reason: T("镜像不可用: %v", err)})
+			continue
+		}
+		report(reporter, Event{Type: EventMirrorTried, Mirror: mirror.Name, URL: RedactURL(url), Total: probe.ContentLength,
+			Message: T("%s 可用（%s）\n", mirror.Name, probe)})
+
+		// 下载文件
+		reportMessage(reporter, T("正在从镜像下载 %s %s版...\n", version, edition))
+		// 未完成的.partial文件会保留，下一个镜像可继续续传，最终由校验和把关
+		if err := downloadWithRetry(ctx, mirror, url, stagingFile, probe, reporter); err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
 			}
-			fmt.Printf("%s 不可用\n", mirror.name)
+			failures = append(failures, mirrorFailure{mirror: mirror.Name, reason: explainAuthError(mirror, err).Error()})
+			continue
 		}
-	}
 
-	if availableMirror == "" {
-		return fmt.Errorf("所有%s版镜像源都不可用", edition)
-	}
+		// 校验SHA-256，工作区指定了distributionSha256Sum时以其为准，否则使用官方校验和
+		reportMessage(reporter, T("正在校验SHA-256..."))
+		expected := expectedSum
+		if expected == "" {
+			expected, err = fetchGradleChecksum(ctx, mirror, url, version, edition)
+			if ctx.Err() != nil {
+				os.Remove(stagingFile)
+				return "", ctx.Err()
+			}
+			if err != nil {
+				reportMessage(reporter, fmt.Sprintf("%s: %v\n", mirror.Name, err))
+				failures = append(failures, mirrorFailure{mirror: mirror.Name, reason: err.Error()})
+				os.Remove(stagingFile)
+				continue
+			}
+		}
+		if err := verifyFileChecksum(stagingFile, expected); err != nil {
+			reportMessage(reporter, T("%s 下载的文件%v，已删除\n", mirror.Name, err))
+			failures = append(failures, mirrorFailure{mirror: mirror.Name, reason: err.Error()})
+			os.Remove(stagingFile)
+			continue
+		}
 
-	// 下载文件
-	tempFile := filepath.Join(cacheDir, version+"-"+edition+".zip")
-	fmt.Printf("正在从镜像下载 %s %s版...\n", version, edition)
+		// 校验通过后替换缓存文件，并在缓存索引中记录来源和校验和，供后续复制前校验
+		if err := os.Rename(stagingFile, gradleZipFile); err != nil {
+			os.Remove(stagingFile)
+			return "", fmt.Errorf(T("移动下载文件到缓存目录失败: %v"), err)
+		}
+		if err := recordCachedGradle(version, edition, mirror.Name, strings.ToLower(expected)); err != nil {
+			return "", err
+		}
 
-	if err := downloadFile(availableMirror, tempFile); err != nil {
-		return err
+		reportMessage(reporter, T("Gradle %s %s版 下载完成\n", version, edition))
+		return gradleZipFile, nil
 	}
 
-	fmt.Printf("Gradle %s %s版 下载完成\n", version, edition)
-	return nil
+	if len(failures) == 0 {
+		return "", fmt.Errorf(T("没有可用于%s版的镜像源"), edition)
+	}
+	return "", &downloadFailedError{edition: edition, failures: failures}
 }
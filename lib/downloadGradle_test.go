@@ -0,0 +1,331 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试用的发行包内容
+var testDistribution = bytes.Repeat([]byte("0123456789abcdef"), 4096)
+
+// 模拟镜像上的一个文件，支持Range和If-Range（由http.ServeContent处理），并记录收到的GET请求
+type testFileServer struct {
+	content []byte
+	etag    string
+
+	// 返回非nil时直接使用该处理函数，用于模拟错误响应或连接中断；n为第几次GET请求（从1开始）
+	override func(w http.ResponseWriter, r *http.Request, n int) bool
+
+	mu       sync.Mutex
+	requests []*http.Request // 收到的GET请求
+}
+
+func (s *testFileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Clone(context.Background()))
+		n := len(s.requests)
+		s.mu.Unlock()
+
+		if s.override != nil && s.override(w, r, n) {
+			return
+		}
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	http.ServeContent(w, r, "gradle.zip", time.Time{}, bytes.NewReader(s.content))
+}
+
+// 收到的GET请求数
+func (s *testFileServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// 第n次GET请求（从1开始）
+func (s *testFileServer) request(n int) *http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[n-1]
+}
+
+func newTestFileServer(t *testing.T, s *testFileServer) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// 发送响应头和部分内容后中断连接，模拟下载中途断线
+func dropAfter(w http.ResponseWriter, content []byte, n int) {
+	w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(content[:n])
+	w.(http.Flusher).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+// 写入已下载的部分及其元数据
+func writePartial(t *testing.T, destPath string, data []byte, meta partialMeta) {
+	t.Helper()
+	partialPath := partialFilePath(destPath)
+	if err := os.WriteFile(partialPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := savePartialMeta(partialPath, meta); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 检查下载完成后的文件内容，并确认临时文件已清理
+func checkDownloaded(t *testing.T, destPath string, want []byte) {
+	t.Helper()
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("%s: %d bytes, content mismatch (want %d bytes)", filepath.Base(destPath), len(data), len(want))
+	}
+	for _, path := range []string{partialFilePath(destPath), partialMetaPath(partialFilePath(destPath))} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", filepath.Base(path))
+		}
+	}
+}
+
+func testProbe(t *testing.T, url string) *probeResult {
+	t.Helper()
+	probe, err := probeMirror(context.Background(), Mirror{}, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return probe
+}
+
+func TestDownloadFileFresh(t *testing.T) {
+	files := &testFileServer{content: testDistribution, etag: `"v1"`}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	written, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, testProbe(t, server.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(len(testDistribution)) {
+		t.Errorf("written = %d, want %d", written, len(testDistribution))
+	}
+	if r := files.request(1); r.Header.Get("Range") != "" {
+		t.Errorf("fresh download sent Range: %q", r.Header.Get("Range"))
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	files := &testFileServer{content: testDistribution, etag: `"v1"`}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	offset := len(testDistribution) / 3
+	writePartial(t, destPath, testDistribution[:offset], partialMeta{URL: server.URL, ETag: `"v1"`, Size: int64(len(testDistribution))})
+
+	written, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, testProbe(t, server.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(len(testDistribution) - offset); written != want {
+		t.Errorf("written = %d, want %d", written, want)
+	}
+
+	r := files.request(1)
+	if got, want := r.Header.Get("Range"), fmt.Sprintf("bytes=%d-", offset); got != want {
+		t.Errorf("Range = %q, want %q", got, want)
+	}
+	if got := r.Header.Get("If-Range"); got != `"v1"` {
+		t.Errorf("If-Range = %q, want %q", got, `"v1"`)
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+// 已下载部分来自其他镜像（ETag不同）时只按大小续传，不发送If-Range
+func TestDownloadFileResumeFromOtherMirror(t *testing.T) {
+	files := &testFileServer{content: testDistribution, etag: `"mirror-b"`}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	offset := len(testDistribution) / 2
+	writePartial(t, destPath, testDistribution[:offset], partialMeta{URL: "https://mirror-a/gradle.zip", ETag: `"mirror-a"`, Size: int64(len(testDistribution))})
+
+	if _, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, testProbe(t, server.URL), nil); err != nil {
+		t.Fatal(err)
+	}
+	r := files.request(1)
+	if r.Header.Get("Range") == "" || r.Header.Get("If-Range") != "" {
+		t.Errorf("Range = %q, If-Range = %q, want Range without If-Range", r.Header.Get("Range"), r.Header.Get("If-Range"))
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+// 探测后服务器上的文件发生变化：If-Range不匹配，服务器返回完整的新文件（200），从头写入
+func TestDownloadFileValidatorChanged(t *testing.T) {
+	updated := bytes.Repeat([]byte("fedcba9876543210"), 4096)
+	files := &testFileServer{content: updated, etag: `"v2"`}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	offset := len(testDistribution) / 2
+	writePartial(t, destPath, testDistribution[:offset], partialMeta{URL: server.URL, ETag: `"v1"`, Size: int64(len(testDistribution))})
+	probe := &probeResult{ContentLength: int64(len(updated)), ETag: `"v1"`, AcceptRanges: true}
+
+	written, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, probe, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(len(updated)) {
+		t.Errorf("written = %d, want %d", written, len(updated))
+	}
+	if got := files.request(1).Header.Get("If-Range"); got != `"v1"` {
+		t.Errorf("If-Range = %q, want %q", got, `"v1"`)
+	}
+	checkDownloaded(t, destPath, updated)
+}
+
+// 服务器忽略Range返回200时丢弃已下载的部分，从头写入
+func TestDownloadFileRangeIgnored(t *testing.T) {
+	files := &testFileServer{content: testDistribution}
+	files.override = func(w http.ResponseWriter, r *http.Request, n int) bool {
+		w.Header().Set("Content-Length", fmt.Sprint(len(testDistribution)))
+		w.Write(testDistribution)
+		return true
+	}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	writePartial(t, destPath, []byte("garbage"), partialMeta{URL: server.URL, Size: int64(len(testDistribution))})
+	probe := &probeResult{ContentLength: int64(len(testDistribution)), AcceptRanges: true}
+
+	if _, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, probe, nil); err != nil {
+		t.Fatal(err)
+	}
+	if files.request(1).Header.Get("Range") != "bytes=7-" {
+		t.Errorf("Range = %q, want bytes=7-", files.request(1).Header.Get("Range"))
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+// 镜像不支持Range时不发送Range请求，直接从头下载
+func TestDownloadFileNoRangeSupport(t *testing.T) {
+	files := &testFileServer{content: testDistribution}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	writePartial(t, destPath, testDistribution[:100], partialMeta{URL: server.URL, Size: int64(len(testDistribution))})
+	probe := &probeResult{ContentLength: int64(len(testDistribution))}
+
+	if _, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, probe, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := files.request(1).Header.Get("Range"); got != "" {
+		t.Errorf("Range = %q, want none", got)
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+// 已下载部分已经完整时服务器返回416，直接完成
+func TestDownloadFileAlreadyComplete(t *testing.T) {
+	files := &testFileServer{content: testDistribution, etag: `"v1"`}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	writePartial(t, destPath, testDistribution, partialMeta{URL: server.URL, ETag: `"v1"`, Size: int64(len(testDistribution))})
+
+	written, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, testProbe(t, server.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 0 {
+		t.Errorf("written = %d, want 0", written)
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+// 连接中途断开时保留已下载的部分，返回可重试的错误
+func TestDownloadFileConnectionDropped(t *testing.T) {
+	half := len(testDistribution) / 2
+	files := &testFileServer{content: testDistribution}
+	files.override = func(w http.ResponseWriter, r *http.Request, n int) bool {
+		dropAfter(w, testDistribution, half)
+		return true
+	}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+	probe := &probeResult{ContentLength: int64(len(testDistribution)), AcceptRanges: true}
+
+	written, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, probe, nil)
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if !isTransientError(err) {
+		t.Errorf("isTransientError(%v) = false, want true", err)
+	}
+	if written != int64(half) {
+		t.Errorf("written = %d, want %d", written, half)
+	}
+	if info, err := os.Stat(partialFilePath(destPath)); err != nil || info.Size() != int64(half) {
+		t.Errorf(".partial = %v, %v, want %d bytes", info, err, half)
+	}
+	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+		t.Errorf("%s exists after a failed download", destPath)
+	}
+}
+
+// 下载的大小与探测结果不一致时丢弃文件，返回不可重试的错误
+func TestDownloadFileSizeMismatch(t *testing.T) {
+	files := &testFileServer{content: testDistribution}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+	probe := &probeResult{ContentLength: int64(len(testDistribution)) + 1, AcceptRanges: true}
+
+	written, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, probe, nil)
+	var sizeErr *sizeMismatchError
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("err = %v, want size mismatch", err)
+	}
+	if written != 0 || isTransientError(err) {
+		t.Errorf("written = %d, transient = %v, want 0, false", written, isTransientError(err))
+	}
+	if _, err := os.Stat(partialFilePath(destPath)); !os.IsNotExist(err) {
+		t.Error(".partial kept after a size mismatch")
+	}
+}
+
+func TestDownloadFileHTTPError(t *testing.T) {
+	files := &testFileServer{content: testDistribution}
+	files.override = func(w http.ResponseWriter, r *http.Request, n int) bool {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return true
+	}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	_, err := downloadFile(context.Background(), http.DefaultClient, server.URL, destPath, testProbe(t, server.URL), nil)
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) || statusErr.code != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want HTTP 503", err)
+	}
+	if !strings.Contains(err.Error(), "503") {
+		t.Errorf("error %q does not mention the status code", err)
+	}
+}
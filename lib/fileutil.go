@@ -0,0 +1,67 @@
+package lib
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// writeAtomic创建的临时文件名为 .<目标文件名>.tmp-<随机数>
+const atomicTempMarker = ".tmp-"
+
+// 先写入同目录下的临时文件，完成后再重命名为目标文件
+// 中途失败或被中断时不会留下写了一半的目标文件
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// 判断文件名是否为writeAtomic正在写入或中断后遗留的临时文件
+func isAtomicTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, atomicTempMarker)
+}
+
+// 以临时文件+重命名的方式写入文件，write负责写入内容
+func writeAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+atomicTempMarker+"*")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+
+	err = write(temp)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// 读取时检查ctx，取消后返回ctx.Err()，使io.Copy能及时中断
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,207 @@
+package lib
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProxyDirect 代理设置的特殊值，表示直接连接，不使用任何代理（包括环境变量中的代理）
+const ProxyDirect = "direct"
+
+var (
+	proxyOverride string // 通过 --proxy 指定的代理
+	configProxy   string // 配置文件中的全局代理
+
+	// 按代理设置缓存的Transport，相同设置的请求共用连接
+	transportsMu sync.Mutex
+	transports   = make(map[string]*http.Transport)
+	tlsSettings  *tls.Config // 自定义的TLS设置，为nil时使用系统默认设置
+
+	offline bool // 离线模式，不发出任何网络请求
+)
+
+// TLSConfig 访问镜像源时的TLS设置，路径均为PEM格式的文件
+type TLSConfig struct {
+	CACerts    []string `yaml:"ca_certs,omitempty"`    // 除系统证书外额外信任的CA证书
+	ClientCert string   `yaml:"client_cert,omitempty"` // mTLS客户端证书
+	ClientKey  string   `yaml:"client_key,omitempty"`  // 客户端证书的私钥
+	Insecure   bool     `yaml:"insecure,omitempty"`    // 不校验服务器证书，存在被窃听和篡改的风险
+}
+
+// SetTLS 加载TLS设置，应用到所有网络请求
+func SetTLS(cfg TLSConfig) error {
+	var settings *tls.Config
+	if len(cfg.CACerts) > 0 || cfg.ClientCert != "" || cfg.ClientKey != "" || cfg.Insecure {
+		settings = &tls.Config{InsecureSkipVerify: cfg.Insecure}
+
+		if len(cfg.CACerts) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			for _, path := range cfg.CACerts {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf(T("读取CA证书失败: %v"), err)
+				}
+				if !pool.AppendCertsFromPEM(data) {
+					return fmt.Errorf(T("CA证书文件中没有有效的PEM证书: %s"), path)
+				}
+			}
+			settings.RootCAs = pool
+		}
+
+		if cfg.ClientCert != "" || cfg.ClientKey != "" {
+			if cfg.ClientCert == "" || cfg.ClientKey == "" {
+				return errors.New(T("客户端证书和私钥需要同时指定"))
+			}
+			cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+			if err != nil {
+				return fmt.Errorf(T("加载客户端证书失败: %v"), err)
+			}
+			settings.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	tlsSettings = settings
+	// 已创建的Transport使用旧设置，需要重新创建
+	transports = make(map[string]*http.Transport)
+	return nil
+}
+
+// 服务器证书不受信任时，在错误中提示如何指定CA证书
+func explainTLSError(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		return fmt.Errorf(T("%v（服务器证书不受信任，如为公司内部CA签发，可用 --ca-cert 或配置文件中的 tls.ca_certs 指定CA证书）"), err)
+	}
+	return err
+}
+
+// 检查代理设置是否有效，空字符串表示未设置
+func validateProxy(raw string) error {
+	if raw == "" || raw == ProxyDirect {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf(T("无效的代理地址: %s"), raw)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+	return fmt.Errorf(T("不支持的代理协议: %s（支持 http、https、socks5、socks5h）"), u.Scheme)
+}
+
+// SetProxy 设置所有网络请求使用的代理（--proxy），优先于配置文件的全局代理和环境变量
+// 可以是 http://、https://、socks5:// 地址，或 direct 表示直接连接
+func SetProxy(raw string) error {
+	if err := validateProxy(raw); err != nil {
+		return err
+	}
+	proxyOverride = raw
+	return nil
+}
+
+// SetOffline 开启或关闭离线模式，开启后只使用缓存中的发行包，所有网络请求都会直接失败
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
+// 离线模式下使用的Transport，拒绝所有请求
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf(T("离线模式下不访问网络: %s"), RedactURL(req.URL.String()))
+}
+
+// 确定请求使用的代理设置：镜像源的proxy > --proxy > 配置文件的proxy，
+// 都未设置时返回空字符串，由 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 环境变量决定
+func effectiveProxy(mirrorProxy string) string {
+	for _, proxy := range []string{mirrorProxy, proxyOverride, configProxy} {
+		if proxy != "" {
+			return proxy
+		}
+	}
+	return ""
+}
+
+// 获取代理设置对应的Transport
+func transportFor(proxy string) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if transport, ok := transports[proxy]; ok {
+		return transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsSettings != nil {
+		transport.TLSClientConfig = tlsSettings.Clone()
+	}
+	switch proxy {
+	case "":
+		transport.Proxy = http.ProxyFromEnvironment
+	case ProxyDirect:
+		transport.Proxy = nil
+	default:
+		// 设置时已校验过格式
+		proxyURL, _ := url.Parse(proxy)
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	transports[proxy] = transport
+	return transport
+}
+
+// 创建HTTP客户端，lib中的所有网络请求都通过它发出
+// mirror为请求所属的镜像源，用于读取其代理和认证设置；不属于某个镜像源时传零值
+func newHTTPClient(mirror Mirror, timeout time.Duration) *http.Client {
+	var transport http.RoundTripper = transportFor(effectiveProxy(mirror.Proxy))
+	switch {
+	case offline:
+		transport = offlineTransport{}
+	case mirror.Auth != nil:
+		transport = &authTransport{base: transport, auth: mirror.Auth, host: mirrorHost(mirror)}
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+// 获取请求target时实际使用的代理地址（隐藏密码），直接连接时返回空字符串
+func describeProxy(mirrorProxy, target string) string {
+	proxy := effectiveProxy(mirrorProxy)
+	if proxy == ProxyDirect {
+		return ""
+	}
+
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return ""
+	}
+	proxyURL, err := transportFor(proxy).Proxy(req)
+	if err != nil || proxyURL == nil {
+		return ""
+	}
+	return proxyURL.Redacted()
+}
+
+// RedactProxy 隐藏代理地址中的密码，便于显示
+func RedactProxy(proxy string) string {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		return proxy
+	}
+	return proxyURL.Redacted()
+}
@@ -0,0 +1,83 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 支持的界面语言
+const (
+	LocaleZhCN = "zh-CN" // 简体中文（源语言）
+	LocaleEn   = "en"    // 英语
+)
+
+// 各语言的消息目录，以简体中文原文为键；简体中文无需目录
+var catalogs = map[string]map[string]string{
+	LocaleEn: messagesEn,
+}
+
+// 当前使用的语言
+var locale = LocaleZhCN
+
+// SetLocale 设置界面语言，支持 zh-CN、en 以及 zh_CN.UTF-8、en_US 等形式
+func SetLocale(lang string) error {
+	normalized, ok := normalizeLocale(lang)
+	if !ok {
+		return fmt.Errorf(T("不支持的语言: %s（可选: %s、%s）"), lang, LocaleZhCN, LocaleEn)
+	}
+	locale = normalized
+	return nil
+}
+
+// GetLocale 获取当前界面语言
+func GetLocale() string {
+	return locale
+}
+
+// DetectLocale 根据 LC_ALL、LC_MESSAGES、LANG 环境变量确定界面语言
+// 均未设置时使用简体中文
+func DetectLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if normalized, ok := normalizeLocale(value); ok {
+			return normalized
+		}
+		// C、POSIX 及其他未翻译的语言使用英语
+		return LocaleEn
+	}
+	return LocaleZhCN
+}
+
+// 将语言标识规范化为支持的语言
+func normalizeLocale(lang string) (string, bool) {
+	// 去掉编码和修饰部分，例如 zh_CN.UTF-8@xxx
+	lang = strings.SplitN(lang, ".", 2)[0]
+	lang = strings.SplitN(lang, "@", 2)[0]
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+
+	switch {
+	case lang == "zh" || strings.HasPrefix(lang, "zh-"):
+		return LocaleZhCN, true
+	case lang == "en" || strings.HasPrefix(lang, "en-"):
+		return LocaleEn, true
+	}
+	return "", false
+}
+
+// T 翻译消息，msg为简体中文原文
+// 未提供args时返回翻译后的字符串（可继续作为格式化字符串使用），否则返回格式化后的结果
+func T(msg string, args ...any) string {
+	if catalog, ok := catalogs[locale]; ok {
+		if translated, ok := catalog[msg]; ok {
+			msg = translated
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
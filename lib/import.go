@@ -0,0 +1,245 @@
+package lib
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// 缓存索引中本地导入的发行包的来源
+const importedMirrorName = "import"
+
+// 导入结果的状态
+const (
+	ImportOK      = "imported" // 已导入缓存
+	ImportExists  = "exists"   // 缓存中已有相同的发行包
+	ImportFailed  = "failed"   // 不是有效的发行包或导入失败
+	ImportSkipped = "skipped"  // 操作被中断，未处理
+)
+
+// ImportResult 导入单个发行包的结果
+type ImportResult struct {
+	File    string `json:"file"`
+	Version string `json:"version,omitempty"`
+	Edition string `json:"edition,omitempty"`
+	SHA256  string `json:"sha256,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+
+	// 导入离线包时安装到的Wrapper目录，未安装到dists目录时为空
+	TargetDir string `json:"target_dir,omitempty"`
+}
+
+// InspectGradleDistribution 检查ZIP文件是否为Gradle发行包，返回其版本号和版本类型
+// 发行包内只能有一个 gradle-X 根目录，且包含 gradle-X/lib 下的jar文件；
+// 含有 src 或 docs 目录的为all版，否则为bin版。文件名为 gradle-X-bin.zip 格式时还要求与内容一致
+func InspectGradleDistribution(zipPath string) (version, edition string, err error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", "", fmt.Errorf(T("不是有效的ZIP文件: %v"), err)
+	}
+	defer reader.Close()
+
+	root := ""
+	hasLib, hasSources := false, false
+	for _, file := range reader.File {
+		name := strings.TrimPrefix(file.Name, "./")
+		if path.IsAbs(name) || slices.Contains(strings.Split(name, "/"), "..") {
+			return "", "", fmt.Errorf(T("非法的文件路径: %s"), file.Name)
+		}
+
+		top, rest, _ := strings.Cut(name, "/")
+		if root == "" {
+			root = top
+		} else if top != root {
+			return "", "", fmt.Errorf(T("发行包结构无效，根目录不唯一: %s, %s"), root, top)
+		}
+
+		switch {
+		case strings.HasPrefix(rest, "lib/") && strings.HasSuffix(rest, ".jar"):
+			hasLib = true
+		case strings.HasPrefix(rest, "src/"), strings.HasPrefix(rest, "docs/"):
+			hasSources = true
+		}
+	}
+
+	version, ok := strings.CutPrefix(root, "gradle-")
+	if !ok {
+		return "", "", fmt.Errorf(T("发行包结构无效，根目录不是 gradle-<版本号>: %s"), root)
+	}
+	if _, err := ParseGradleVersion(version); err != nil {
+		return "", "", err
+	}
+	if !hasLib {
+		return "", "", fmt.Errorf(T("发行包结构无效，缺少 %s/lib 目录"), root)
+	}
+
+	edition = "bin"
+	if hasSources {
+		edition = "all"
+	}
+
+	// 文件名可能被改过，以内容为准，但明显不一致时拒绝导入
+	if nameVersion, nameEdition, err := extractGradleVersion(filepath.Base(zipPath)); err == nil &&
+		(nameVersion != version || nameEdition != edition) {
+		return "", "", fmt.Errorf(T("文件名与发行包内容不一致: 文件名为 %s %s版，内容为 %s %s版"), nameVersion, nameEdition, version, edition)
+	}
+	return version, edition, nil
+}
+
+// ImportGradle 从本地ZIP文件或目录（其中的所有ZIP文件）导入Gradle发行包到缓存，不访问网络
+// 发行包旁有同名的.sha256文件时校验其SHA-256
+// 返回每个文件的导入结果；有文件导入失败或ctx被取消时同时返回错误
+func ImportGradle(ctx context.Context, source string, reporter Reporter) ([]ImportResult, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf(T("读取导入文件失败: %v"), err)
+	}
+
+	files := []string{source}
+	if info.IsDir() {
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			return nil, fmt.Errorf(T("读取导入目录失败: %v"), err)
+		}
+		files = nil
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(strings.ToLower(entry.Name()), ".zip") {
+				files = append(files, filepath.Join(source, entry.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf(T("目录中没有ZIP文件: %s"), source)
+		}
+		sort.Strings(files)
+	}
+
+	results := make([]ImportResult, 0, len(files))
+	failed := 0
+	for _, file := range files {
+		if ctx.Err() != nil {
+			results = append(results, ImportResult{File: file, Status: ImportSkipped})
+			continue
+		}
+
+		result := importGradleZip(ctx, file, "", reporter)
+		if result.Status == ImportFailed {
+			reportMessage(reporter, T("❌ %s 导入失败: %s\n", filepath.Base(file), result.Error))
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	if failed > 0 {
+		return results, fmt.Errorf(T("%d 个发行包导入失败"), failed)
+	}
+	return results, nil
+}
+
+// 导入单个发行包：检查校验和与结构，复制到缓存目录并记录到缓存索引
+// expectedSum非空时（如离线包清单中的校验和）以其为准，否则按同名的.sha256文件校验（如果存在）
+func importGradleZip(ctx context.Context, zipPath, expectedSum string, reporter Reporter) ImportResult {
+	result := ImportResult{File: zipPath}
+	fail := func(err error) ImportResult {
+		result.Status = ImportFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	reportMessage(reporter, T("正在检查 %s ...\n", filepath.Base(zipPath)))
+	sum, err := FileSHA256(zipPath)
+	if err != nil {
+		return fail(fmt.Errorf(T("计算校验和失败: %v"), err))
+	}
+	result.SHA256 = sum
+
+	if expectedSum == "" {
+		if data, err := os.ReadFile(checksumFilePath(zipPath)); err == nil {
+			expectedSum = sha256Pattern.FindString(string(data))
+			if expectedSum == "" {
+				return fail(fmt.Errorf(T("校验和文件中没有有效的SHA-256: %s"), checksumFilePath(zipPath)))
+			}
+			reportMessage(reporter, T("已按 %s 校验SHA-256\n", filepath.Base(checksumFilePath(zipPath))))
+		}
+	}
+	if expectedSum != "" && !strings.EqualFold(expectedSum, sum) {
+		return fail(fmt.Errorf(T("校验和不匹配: 期望 %s, 实际 %s"), expectedSum, sum))
+	}
+
+	version, edition, err := InspectGradleDistribution(zipPath)
+	if err != nil {
+		return fail(err)
+	}
+	result.Version, result.Edition = version, edition
+
+	if err := os.MkdirAll(GetCacheDir(), os.ModePerm); err != nil {
+		return fail(fmt.Errorf(T("创建缓存目录失败: %v"), err))
+	}
+	unlock := lockCachedGradle(version, edition)
+	defer unlock()
+
+	// 缓存中已有完整的发行包时，相同的跳过，不同的不覆盖
+	cachePath := CachedGradlePath(version, edition)
+	if _, err := os.Stat(cachePath); err == nil && VerifyCachedGradle(version, edition, "") == nil {
+		recorded, _ := CachedChecksum(version, edition)
+		if !strings.EqualFold(recorded, sum) {
+			return fail(fmt.Errorf(T("缓存中已有不同的Gradle %s %s版（SHA-256 %s），如需替换请先使用 clear-cache 删除"), version, edition, recorded))
+		}
+		reportMessage(reporter, T("Gradle %s %s版 已存在于缓存目录中\n", version, edition))
+		result.Status = ImportExists
+		return result
+	}
+
+	if err := copyFileWithProgress(ctx, zipPath, cachePath, reporter); err != nil {
+		return fail(fmt.Errorf(T("复制文件失败: %v"), err))
+	}
+	if err := recordCachedGradle(version, edition, importedMirrorName, strings.ToLower(sum)); err != nil {
+		removeCachedGradle(cachePath)
+		return fail(err)
+	}
+
+	reportMessage(reporter, T("✅ 已导入 Gradle %s %s版\n", version, edition))
+	result.Status = ImportOK
+	return result
+}
+
+// 复制文件并报告进度，先写入临时文件，失败或被中断时不留下不完整的目标文件
+func copyFileWithProgress(ctx context.Context, sourcePath, targetPath string, reporter Reporter) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return errors.New(T("不是普通文件"))
+	}
+
+	report(reporter, Event{Type: EventStarted, Task: TaskCopy, Path: targetPath, Total: info.Size()})
+	progress := newProgressWriter(reporter, TaskCopy, targetPath, 0, info.Size())
+	err = writeAtomic(targetPath, 0644, func(w io.Writer) error {
+		_, err := io.Copy(io.MultiWriter(w, progress), &contextReader{ctx: ctx, r: source})
+		return err
+	})
+	if err != nil {
+		report(reporter, Event{Type: EventDone, Task: TaskCopy, Path: targetPath, Error: err.Error()})
+		return err
+	}
+	report(reporter, Event{Type: EventDone, Task: TaskCopy, Path: targetPath, Current: info.Size(), Total: info.Size()})
+	return nil
+}
@@ -0,0 +1,137 @@
+package lib
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 在dir中创建名为name的ZIP文件，包含给定路径的空文件，返回其路径
+func writeZip(t *testing.T, dir, name string, files ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	w := zip.NewWriter(file)
+	for _, f := range files {
+		if _, err := w.CreateHeader(&zip.FileHeader{Name: f}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInspectGradleDistribution(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gradle-8.7-bin.zip.txt"), []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		version string
+		edition string
+		wantErr string // 错误信息应包含的内容，为空表示成功
+	}{
+		{"bin", writeZip(t, dir, "gradle-8.7-bin.zip", "gradle-8.7/", "gradle-8.7/bin/gradle", "gradle-8.7/lib/gradle-core-8.7.jar"), "8.7", "bin", ""},
+		{"all", writeZip(t, dir, "gradle-8.7-all.zip", "gradle-8.7/lib/gradle-core-8.7.jar", "gradle-8.7/src/core/Main.java", "gradle-8.7/docs/index.html"), "8.7", "all", ""},
+		{"renamed", writeZip(t, dir, "offline.zip", "./gradle-8.6/lib/gradle-core-8.6.jar"), "8.6", "bin", ""},
+		{"not a zip", filepath.Join(dir, "gradle-8.7-bin.zip.txt"), "", "", "ZIP"},
+		{"not gradle", writeZip(t, dir, "commons.zip", "commons-io-2.15/lib/commons-io.jar"), "", "", "commons-io-2.15"},
+		{"invalid version", writeZip(t, dir, "gradle-latest.zip", "gradle-latest/lib/gradle.jar"), "", "", "latest"},
+		{"two roots", writeZip(t, dir, "two.zip", "gradle-8.7/lib/gradle.jar", "gradle-8.6/lib/gradle.jar"), "", "", "gradle-8.6"},
+		{"missing lib", writeZip(t, dir, "nolib.zip", "gradle-8.7/bin/gradle", "gradle-8.7/lib/README"), "", "", "gradle-8.7/lib"},
+		{"parent path", writeZip(t, dir, "parent.zip", "gradle-8.7/lib/gradle.jar", "gradle-8.7/../../evil.sh"), "", "", "../../evil.sh"},
+		{"absolute path", writeZip(t, dir, "abs.zip", "/gradle-8.7/lib/gradle.jar"), "", "", "/gradle-8.7/lib/gradle.jar"},
+		{"version mismatch", writeZip(t, dir, "gradle-8.6-bin.zip", "gradle-8.7/lib/gradle.jar"), "", "", "8.6"},
+		{"edition mismatch", writeZip(t, dir, "gradle-8.5-all.zip", "gradle-8.5/lib/gradle.jar"), "", "", "8.5"},
+	}
+
+	defer SetLocale(GetLocale())
+	if err := SetLocale(LocaleEn); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, edition, err := InspectGradleDistribution(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.version || edition != tt.edition {
+				t.Errorf("got %s %s, want %s %s", version, edition, tt.version, tt.edition)
+			}
+		})
+	}
+}
+
+func TestImportGradleChecksum(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	dir := t.TempDir()
+	zipPath := writeZip(t, dir, "gradle-8.7-bin.zip", "gradle-8.7/lib/gradle-core-8.7.jar")
+	sum, err := FileSHA256(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 校验和不一致时拒绝导入，缓存保持为空
+	wrong := strings.Repeat("0", 64)
+	if err := os.WriteFile(checksumFilePath(zipPath), []byte(wrong+"  gradle-8.7-bin.zip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := ImportGradle(context.Background(), zipPath, nil)
+	if err == nil || len(results) != 1 || results[0].Status != ImportFailed || !strings.Contains(results[0].Error, wrong) {
+		t.Fatalf("results = %+v, err = %v, want checksum mismatch", results, err)
+	}
+	if _, err := os.Stat(CachedGradlePath("8.7", "bin")); !os.IsNotExist(err) {
+		t.Error("distribution with a wrong checksum was copied into the cache")
+	}
+
+	// 校验和文件中没有SHA-256时同样拒绝
+	if err := os.WriteFile(checksumFilePath(zipPath), []byte("not a checksum"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if results, err := ImportGradle(context.Background(), zipPath, nil); err == nil || results[0].Status != ImportFailed {
+		t.Fatalf("results = %+v, err = %v, want failure", results, err)
+	}
+
+	// 校验和一致时导入，再次导入时跳过
+	if err := os.WriteFile(checksumFilePath(zipPath), []byte(strings.ToUpper(sum)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{ImportOK, ImportExists} {
+		results, err := ImportGradle(context.Background(), dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Status != want || results[0].Version != "8.7" || results[0].Edition != "bin" {
+			t.Errorf("results = %+v, want %s", results, want)
+		}
+	}
+
+	entries, err := ListCacheEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Mirror != importedMirrorName || entries[0].SHA256 != sum {
+		t.Errorf("cache entries = %+v", entries)
+	}
+}
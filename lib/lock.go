@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 等待临时文件释放时的检查间隔
+const lockPollInterval = time.Second
+
+// 当前平台不支持检测文件占用，仅用于内部判断
+var errLockDetectionUnsupported = errors.New("lock detection unsupported")
+
+// LockHolder 正在使用Gradle临时文件的进程
+type LockHolder struct {
+	PID     int    `json:"pid"`               // 进程号，无法确定时为0
+	Command string `json:"command,omitempty"` // 进程名称
+	Path    string `json:"path"`              // 被使用的文件
+}
+
+func (h LockHolder) String() string {
+	if h.PID <= 0 {
+		return T("未知进程")
+	}
+	if h.Command == "" {
+		return fmt.Sprintf("PID %d", h.PID)
+	}
+	return fmt.Sprintf("PID %d (%s)", h.PID, h.Command)
+}
+
+// 以逗号连接进程描述
+func describeLockHolders(holders []LockHolder) string {
+	names := make([]string, 0, len(holders))
+	for _, holder := range holders {
+		names = append(names, holder.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// GradleLockHolders 查找正在使用指定Gradle版本的.lck或.part文件的进程
+// 当前平台不支持检测时返回错误
+func GradleLockHolders(info GradleFileInfo) ([]LockHolder, error) {
+	var holders []LockHolder
+	for _, path := range []string{info.LockFile, info.PartFile} {
+		if path == "" {
+			continue
+		}
+		found, err := fileLockHolders(path)
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, found...)
+	}
+	return holders, nil
+}
+
+// 删除临时文件前检查是否有MCreator或Gradle进程正在使用，避免破坏正在进行的下载
+// opts.Force为true时只给出警告；opts.Wait大于0时等待进程释放，最多等待opts.Wait
+// 返回是否等待过其他进程；不支持检测的平台直接放行
+func checkGradleLock(ctx context.Context, info GradleFileInfo, opts ProcessOptions, reporter Reporter) (bool, error) {
+	holders, err := GradleLockHolders(info)
+	if err != nil || len(holders) == 0 {
+		return false, nil
+	}
+
+	if opts.Force {
+		reportMessage(reporter, T("⚠️ 临时文件正在被 %s 使用，已指定 --force，仍然删除\n", describeLockHolders(holders)))
+		return false, nil
+	}
+
+	if opts.Wait > 0 {
+		reportMessage(reporter, T("临时文件正在被 %s 使用，等待其释放（最多 %s）...\n", describeLockHolders(holders), opts.Wait))
+		deadline := time.Now().Add(opts.Wait)
+		ticker := time.NewTicker(lockPollInterval)
+		defer ticker.Stop()
+
+		for time.Now().Before(deadline) {
+			select {
+			case <-ctx.Done():
+				return true, ctx.Err()
+			case <-ticker.C:
+			}
+			if holders, err = GradleLockHolders(info); err != nil || len(holders) == 0 {
+				reportMessage(reporter, T("临时文件已释放"))
+				return true, nil
+			}
+		}
+	}
+
+	return opts.Wait > 0, fmt.Errorf(T("Gradle %s %s版的临时文件正在被 %s 使用，可能仍在下载中；请等待下载结束或关闭MCreator后重试，或使用 --force 强制处理"),
+		info.Version, info.Edition, describeLockHolders(holders))
+}
@@ -0,0 +1,113 @@
+package lib
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// 查找持有文件锁或打开了文件的进程
+// 先用fcntl F_GETLK检查文件上的记录锁（Gradle通过FileChannel.lock加锁），
+// 再扫描/proc中打开了该文件的java进程（下载.part文件时不一定加锁）
+func fileLockHolders(path string) ([]LockHolder, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+
+	var holders []LockHolder
+	seen := make(map[int]bool)
+	if pid, ok := fcntlLockHolder(path); ok {
+		holders = append(holders, LockHolder{PID: pid, Command: processCommand(pid), Path: path})
+		seen[pid] = true
+	}
+	for _, pid := range javaProcessesUsing(path) {
+		if !seen[pid] {
+			holders = append(holders, LockHolder{PID: pid, Command: processCommand(pid), Path: path})
+			seen[pid] = true
+		}
+	}
+	return holders, nil
+}
+
+// 检查文件上是否有其他进程持有的记录锁，返回持有锁的进程号
+// OFD锁无法确定进程，此时进程号为0
+func fcntlLockHolder(path string) (int, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	// 测试能否对整个文件加写锁，有冲突时内核返回冲突的锁
+	lock := syscall.Flock_t{Type: syscall.F_WRLCK, Whence: io.SeekStart}
+	if err := syscall.FcntlFlock(file.Fd(), syscall.F_GETLK, &lock); err != nil {
+		return 0, false
+	}
+	if lock.Type == syscall.F_UNLCK {
+		return 0, false
+	}
+	return max(int(lock.Pid), 0), true
+}
+
+// 扫描/proc，找出打开了指定文件的java进程
+// 没有权限读取的进程会被跳过
+func javaProcessesUsing(path string) []int {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	var pids []int
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil || pid == os.Getpid() || !isJavaProcess(pid) {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			if link, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil && link == target {
+				pids = append(pids, pid)
+				break
+			}
+		}
+	}
+	return pids
+}
+
+// 判断进程是否为java进程（MCreator和Gradle守护进程都运行在JVM中）
+func isJavaProcess(pid int) bool {
+	comm, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm"))
+	if err == nil && strings.TrimSpace(string(comm)) == "java" {
+		return true
+	}
+	return strings.HasPrefix(processCommand(pid), "java")
+}
+
+// 获取进程的可执行文件名称
+func processCommand(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	cmdline, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil || len(cmdline) == 0 {
+		return ""
+	}
+	name, _, _ := strings.Cut(string(cmdline), "\x00")
+	return filepath.Base(name)
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package lib
+
+// 其他平台暂不支持检测文件是否被占用
+func fileLockHolders(path string) ([]LockHolder, error) {
+	return nil, errLockDetectionUnsupported
+}
@@ -1,15 +1,14 @@
 package lib
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/schollz/progressbar/v3"
 )
 
 // GradleFileInfo 存储Gradle文件信息
@@ -19,17 +18,22 @@ type GradleFileInfo struct {
 	LockFile  string // .lck文件完整路径
 	PartFile  string // .part文件完整路径
 	TargetDir string // 目标目录
+
+	// 工作区gradle-wrapper.properties中的distributionSha256Sum，未知时为空
+	ExpectedSum string
 }
 
 // 从文件名中提取Gradle版本信息
 func extractGradleVersion(filename string) (version, edition string, err error) {
-	// 正则表达式匹配 gradle-版本号-版本类型.zip 格式
-	// 例如: gradle-8.14.2-bin.zip
-	re := regexp.MustCompile(`gradle-(\d+\.\d+\.\d+)-(bin|all)\.zip`)
-	matches := re.FindStringSubmatch(filename)
-
+	// 匹配 gradle-版本号-版本类型.zip 格式
+	// 例如: gradle-8.14.2-bin.zip、gradle-8.7-bin.zip、gradle-9.0-rc-1-all.zip
+	matches := gradleZipPattern.FindStringSubmatch(filename)
 	if len(matches) != 3 {
-		err = fmt.Errorf("无法从文件名中提取Gradle版本信息: %s", filename)
+		err = fmt.Errorf(T("无法从文件名中提取Gradle版本信息: %s"), filename)
+		return
+	}
+
+	if _, err = ParseGradleVersion(matches[1]); err != nil {
 		return
 	}
 
@@ -44,7 +48,7 @@ func ScanMCreatorGradleFiles(gradlePath string) ([]GradleFileInfo, error) {
 
 	// 检查目录是否存在
 	if _, err := os.Stat(gradlePath); os.IsNotExist(err) {
-		return results, fmt.Errorf("gradle目录不存在: %s", gradlePath)
+		return results, fmt.Errorf(T("gradle目录不存在: %s"), gradlePath)
 	}
 
 	// 遍历目录结构
@@ -67,10 +71,11 @@ func ScanMCreatorGradleFiles(gradlePath string) ([]GradleFileInfo, error) {
 					return nil
 				}
 
-				// 查找是否已经存在相同版本的信息
+				// 查找是否已经存在同一目录中相同版本的信息
+				// 同一版本可能有多个哈希目录（distributionUrl不同），每个目录都需要单独安装
 				found := false
 				for i, result := range results {
-					if result.Version == version && result.Edition == edition {
+					if result.Version == version && result.Edition == edition && result.TargetDir == filepath.Dir(path) {
 						// 更新现有记录
 						if strings.HasSuffix(filename, ".lck") {
 							results[i].LockFile = path
@@ -105,139 +110,281 @@ func ScanMCreatorGradleFiles(gradlePath string) ([]GradleFileInfo, error) {
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("扫描Gradle目录失败: %v", err)
+		return nil, fmt.Errorf(T("扫描Gradle目录失败: %v"), err)
 	}
 
+	// 按版本号从旧到新排序
+	sort.SliceStable(results, func(i, j int) bool {
+		vi, _ := ParseGradleVersion(results[i].Version)
+		vj, _ := ParseGradleVersion(results[j].Version)
+		return vi.Compare(vj) < 0
+	})
+
 	return results, nil
 }
 
 // 删除.lck和.part文件
-func DeleteGradleTempFiles(info GradleFileInfo) error {
+func DeleteGradleTempFiles(info GradleFileInfo, reporter Reporter) error {
 	if info.LockFile != "" {
 		if err := os.Remove(info.LockFile); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("删除.lck文件失败: %v", err)
+			return fmt.Errorf(T("删除.lck文件失败: %v"), err)
 		}
-		fmt.Printf("已删除: %s\n", filepath.Base(info.LockFile))
+		report(reporter, Event{Type: EventFileDeleted, Path: info.LockFile, Message: T("已删除: %s\n", filepath.Base(info.LockFile))})
 	}
 
 	if info.PartFile != "" {
 		if err := os.Remove(info.PartFile); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("删除.part文件失败: %v", err)
+			return fmt.Errorf(T("删除.part文件失败: %v"), err)
 		}
-		fmt.Printf("已删除: %s\n", filepath.Base(info.PartFile))
+		report(reporter, Event{Type: EventFileDeleted, Path: info.PartFile, Message: T("已删除: %s\n", filepath.Base(info.PartFile))})
 	}
 
 	return nil
 }
 
 // 复制Gradle文件到目标目录
-func CopyGradleToTarget(version, edition, targetDir string) error {
-	// 下载Gradle
-	if err := DownloadGradle(version, edition); err != nil {
-		return fmt.Errorf("下载Gradle失败: %v", err)
+// expectedSum非空时，缓存文件必须与其一致才会复制
+// 先复制到临时文件再重命名，复制失败或ctx被取消时不会留下不完整的ZIP文件
+func CopyGradleToTarget(ctx context.Context, version, edition, targetDir, expectedSum string, reporter Reporter) error {
+	// 复制完成前持有缓存锁，确保复制的正是下载时校验过的文件
+	unlock := lockCachedGradle(version, edition)
+	defer unlock()
+
+	// 下载Gradle（缓存中已有时校验缓存文件）
+	sourceFile, err := downloadCachedGradle(ctx, version, edition, expectedSum, reporter)
+	if err != nil {
+		return fmt.Errorf(T("下载Gradle失败: %v"), err)
 	}
 
-	// 源文件路径（缓存目录）
-	cacheDir := GetCacheDir()
-	sourceFile := filepath.Join(cacheDir, version+"-"+edition+".zip")
-
 	// 目标文件路径
 	targetFile := filepath.Join(targetDir, fmt.Sprintf("gradle-%s-%s.zip", version, edition))
 
-	// 获取源文件大小用于进度条
-	sourceInfo, err := os.Stat(sourceFile)
-	if err != nil {
-		return fmt.Errorf("获取源文件信息失败: %v", err)
+	if err := copyFileWithProgress(ctx, sourceFile, targetFile, reporter); err != nil {
+		return fmt.Errorf(T("复制文件失败: %v"), err)
 	}
-	fileSize := sourceInfo.Size()
+	reportMessage(reporter, T("✅ 已复制: %s -> %s\n",
+		filepath.Base(sourceFile),
+		filepath.Join(filepath.Base(targetDir), filepath.Base(targetFile))))
 
-	// 打开源文件
-	source, err := os.Open(sourceFile)
-	if err != nil {
-		return fmt.Errorf("打开源文件失败: %v", err)
+	return nil
+}
+
+// 根据工作区的Wrapper配置，为待处理的Gradle版本填入distributionSha256Sum
+// 优先按Wrapper安装目录精确匹配，其次按版本号和版本类型匹配
+func applyWorkspaceChecksums(gradlePath string, files []GradleFileInfo, workspaceDirs []string) {
+	byDir := make(map[string]string)
+	byVersion := make(map[string]string)
+	for _, dir := range workspaceDirs {
+		status := CheckWorkspace(gradlePath, dir)
+		if status.Err != nil || status.Properties.DistributionSha256Sum == "" {
+			continue
+		}
+		sum := status.Properties.DistributionSha256Sum
+		byDir[filepath.Clean(status.InstallDir)] = sum
+		byVersion[status.Version+"-"+status.Edition] = sum
 	}
-	defer source.Close()
 
-	// 创建目标文件
-	target, err := os.Create(targetFile)
-	if err != nil {
-		return fmt.Errorf("创建目标文件失败: %v", err)
-	}
-	defer target.Close()
-
-	// 创建复制进度条
-	bar := progressbar.NewOptions64(
-		fileSize,
-		progressbar.OptionSetDescription("📋 复制进度"),
-		progressbar.OptionSetWriter(os.Stderr),
-		progressbar.OptionShowBytes(true),
-		progressbar.OptionSetWidth(60),
-		progressbar.OptionThrottle(50*time.Millisecond),
-		progressbar.OptionShowCount(),
-		progressbar.OptionOnCompletion(func() {
-			fmt.Fprint(os.Stderr, "✅ 复制完成\n")
-		}),
-		progressbar.OptionSpinnerType(9),
-		progressbar.OptionFullWidth(),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "█",
-			SaucerHead:    "🟢",
-			SaucerPadding: "░",
-			BarStart:      "|",
-			BarEnd:        "|",
-		}),
-	)
-
-	// 使用带进度条的文件复制
-	_, err = io.Copy(io.MultiWriter(target, bar), source)
+	for i := range files {
+		if sum, ok := byDir[filepath.Clean(files[i].TargetDir)]; ok {
+			files[i].ExpectedSum = sum
+		} else if sum, ok := byVersion[files[i].Version+"-"+files[i].Edition]; ok {
+			files[i].ExpectedSum = sum
+		}
+	}
+}
+
+// Gradle版本的处理状态
+const (
+	StatusOK      = "ok"      // 处理成功
+	StatusFailed  = "failed"  // 处理失败
+	StatusSkipped = "skipped" // 操作被中断，未处理
+)
+
+// GradleResult 单个Gradle版本的处理结果
+type GradleResult struct {
+	Version   string `json:"version"`
+	Edition   string `json:"edition"`
+	TargetDir string `json:"target_dir"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ProcessOptions 处理Gradle版本的选项
+type ProcessOptions struct {
+	Unpack bool          // 按Gradle Wrapper的目录结构解压发行包并写入.ok标记，否则只放置ZIP文件
+	Jobs   int           // 最多同时处理的版本数量
+	Force  bool          // 临时文件正在被其他进程使用时仍然删除
+	Wait   time.Duration // 临时文件正在被其他进程使用时等待其释放的最长时间，为0时不等待
+}
+
+// 处理单个Gradle版本：删除临时文件、下载并复制，按需解压
+func processGradleVersion(ctx context.Context, fileInfo GradleFileInfo, opts ProcessOptions, reporter Reporter) error {
+	// 离线模式下缓存中没有该版本时无法修复，保留现有文件
+	if _, err := os.Stat(CachedGradlePath(fileInfo.Version, fileInfo.Edition)); offline && err != nil {
+		return fmt.Errorf(T("离线模式: 缓存中没有Gradle %s %s版，请先使用 import 命令导入"), fileInfo.Version, fileInfo.Edition)
+	}
+
+	// 1. 删除临时文件
+	reportMessage(reporter, T("1. 删除临时文件..."))
+	waited, err := checkGradleLock(ctx, fileInfo, opts, reporter)
 	if err != nil {
-		return fmt.Errorf("复制文件失败: %v", err)
+		return err
+	}
+	// 等待期间其他进程可能已经完成安装
+	zipPath := filepath.Join(fileInfo.TargetDir, fmt.Sprintf("gradle-%s-%s.zip", fileInfo.Version, fileInfo.Edition))
+	if _, err := os.Stat(wrapperMarkerPath(zipPath)); waited && err == nil {
+		reportMessage(reporter, T("Gradle %s %s版 已安装，无需处理\n", fileInfo.Version, fileInfo.Edition))
+		return nil
+	}
+	if err := DeleteGradleTempFiles(fileInfo, reporter); err != nil {
+		return err
 	}
 
-	fmt.Printf("✅ 已复制: %s -> %s\n",
-		filepath.Base(sourceFile),
-		filepath.Join(filepath.Base(targetDir), filepath.Base(targetFile)))
+	// 2. 下载并复制Gradle
+	reportMessage(reporter, T("2. 下载并复制Gradle..."))
+	if fileInfo.ExpectedSum != "" {
+		reportMessage(reporter, T("使用工作区指定的distributionSha256Sum: %s\n", fileInfo.ExpectedSum))
+	}
+	if err := CopyGradleToTarget(ctx, fileInfo.Version, fileInfo.Edition, fileInfo.TargetDir, fileInfo.ExpectedSum, reporter); err != nil {
+		return err
+	}
 
+	// 3. 解压发行包
+	if opts.Unpack {
+		reportMessage(reporter, T("3. 解压Gradle发行包..."))
+		if err := UnpackGradleDistribution(ctx, fileInfo.Version, fileInfo.Edition, fileInfo.TargetDir, reporter); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// DefaultJobs 默认同时处理的Gradle版本数量
+const DefaultJobs = 3
+
+// 并发处理多个Gradle版本，最多同时处理jobs个；单个版本失败不影响其他版本
+// process处理第i个版本，并发时传入的reporter会在说明信息前加上版本号
+// ctx被取消后不再开始新的版本，未开始的版本标记为skipped
+func processGradleFiles(ctx context.Context, files []GradleFileInfo, jobs int, reporter Reporter,
+	process func(ctx context.Context, i int, fileInfo GradleFileInfo, reporter Reporter) error) []GradleResult {
+	results := make([]GradleResult, len(files))
+	for i, fileInfo := range files {
+		results[i] = GradleResult{
+			Version:   fileInfo.Version,
+			Edition:   fileInfo.Edition,
+			TargetDir: fileInfo.TargetDir,
+			Status:    StatusSkipped,
+		}
+	}
+
+	jobs = max(1, min(jobs, len(files)))
+	slots := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, fileInfo := range files {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		r := reporter
+		if jobs > 1 {
+			r = prefixReporter{reporter: reporter, prefix: fmt.Sprintf("[%s %s] ", fileInfo.Version, fileInfo.Edition)}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := process(ctx, i, fileInfo, r); err != nil {
+				results[i].Status = StatusFailed
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Status = StatusOK
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// 输出每个Gradle版本的处理结果
+func reportGradleSummary(reporter Reporter, results []GradleResult) {
+	var summary strings.Builder
+	summary.WriteString(T("\n处理结果:\n"))
+	for _, result := range results {
+		switch result.Status {
+		case StatusOK:
+			summary.WriteString(T("  ✅ Gradle %s %s版\n", result.Version, result.Edition))
+		case StatusFailed:
+			// 多行错误信息缩进显示
+			detail := strings.ReplaceAll(result.Error, "\n", "\n    ")
+			summary.WriteString(T("  ❌ Gradle %s %s版: %s\n", result.Version, result.Edition, detail))
+		default:
+			summary.WriteString(T("  ⏭️ Gradle %s %s版: 未处理\n", result.Version, result.Edition))
+		}
+	}
+	reportMessage(reporter, summary.String())
+}
+
+// 统计处理失败的版本数量
+func countFailed(results []GradleResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
 // 处理MCreator Gradle下载问题
-func ProcessMCreatorGradle(gradlePath string) error {
-	fmt.Println("正在扫描MCreator Gradle目录...")
+// workspaceDirs为MCreator工作区目录，用于读取distributionSha256Sum
+// 最多同时处理opts.Jobs个版本，单个版本失败时继续处理其他版本
+// 返回每个版本的处理结果；有版本失败或ctx被取消时同时返回错误
+func ProcessMCreatorGradle(ctx context.Context, gradlePath string, workspaceDirs []string, opts ProcessOptions, reporter Reporter) ([]GradleResult, error) {
+	reportMessage(reporter, T("正在扫描MCreator Gradle目录..."))
 
 	// 扫描.lck和.part文件
 	files, err := ScanMCreatorGradleFiles(gradlePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	applyWorkspaceChecksums(gradlePath, files, workspaceDirs)
 
 	if len(files) == 0 {
-		fmt.Println("未找到需要处理的Gradle文件")
-		return nil
+		reportMessage(reporter, T("未找到需要处理的Gradle文件"))
+		return nil, nil
 	}
 
-	fmt.Printf("找到 %d 个需要处理的Gradle版本:\n", len(files))
+	reportMessage(reporter, T("找到 %d 个需要处理的Gradle版本:\n", len(files)))
 
 	// 处理每个Gradle版本
-	for i, fileInfo := range files {
-		fmt.Printf("\n[%d/%d] 处理Gradle %s %s版:\n",
-			i+1, len(files), fileInfo.Version, fileInfo.Edition)
+	results := processGradleFiles(ctx, files, opts.Jobs, reporter, func(ctx context.Context, i int, fileInfo GradleFileInfo, reporter Reporter) error {
+		reportMessage(reporter, T("\n[%d/%d] 处理Gradle %s %s版:\n",
+			i+1, len(files), fileInfo.Version, fileInfo.Edition))
 
-		// 1. 删除临时文件
-		fmt.Println("1. 删除临时文件...")
-		if err := DeleteGradleTempFiles(fileInfo); err != nil {
-			return err
-		}
-
-		// 2. 下载并复制Gradle
-		fmt.Println("2. 下载并复制Gradle...")
-		if err := CopyGradleToTarget(fileInfo.Version, fileInfo.Edition, fileInfo.TargetDir); err != nil {
+		if err := processGradleVersion(ctx, fileInfo, opts, reporter); err != nil {
+			reportMessage(reporter, T("❌ Gradle %s %s版处理失败: %v\n", fileInfo.Version, fileInfo.Edition, err))
 			return err
 		}
+		reportMessage(reporter, T("✅ Gradle %s %s版处理完成\n", fileInfo.Version, fileInfo.Edition))
+		return nil
+	})
 
-		fmt.Printf("✅ Gradle %s %s版处理完成\n", fileInfo.Version, fileInfo.Edition)
+	if len(files) > 1 {
+		reportGradleSummary(reporter, results)
+	}
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	if failed := countFailed(results); failed > 0 {
+		return results, fmt.Errorf(T("%d 个Gradle版本处理失败"), failed)
 	}
 
-	fmt.Printf("\n✅ 所有Gradle版本处理完成！共处理了 %d 个版本\n", len(files))
-	return nil
+	reportMessage(reporter, T("\n✅ 所有Gradle版本处理完成！共处理了 %d 个版本\n", len(files)))
+	return results, nil
 }
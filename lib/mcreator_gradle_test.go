@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanMCreatorGradleFilesHashDirs(t *testing.T) {
+	distsDir := t.TempDir()
+	dirA := filepath.Join(distsDir, "gradle-8.7-bin", "hashA")
+	dirB := filepath.Join(distsDir, "gradle-8.7-bin", "hashB")
+	makeDist(t, distsDir, "8.6", map[string]string{"gradle-8.6-bin.zip.part": ""})
+	for _, path := range []string{
+		filepath.Join(dirA, "gradle-8.7-bin.zip.lck"),
+		filepath.Join(dirA, "gradle-8.7-bin.zip.part"),
+		filepath.Join(dirB, "gradle-8.7-bin.zip.lck"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := ScanMCreatorGradleFiles(distsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("ScanMCreatorGradleFiles returned %d entries, want 3: %+v", len(files), files)
+	}
+	if files[0].Version != "8.6" {
+		t.Errorf("files[0].Version = %q, want 8.6", files[0].Version)
+	}
+
+	byDir := make(map[string]GradleFileInfo)
+	for _, file := range files[1:] {
+		byDir[file.TargetDir] = file
+	}
+	if a := byDir[dirA]; a.LockFile == "" || a.PartFile == "" {
+		t.Errorf("%s: %+v, want both .lck and .part", dirA, a)
+	}
+	if b := byDir[dirB]; b.LockFile == "" || b.PartFile != "" {
+		t.Errorf("%s: %+v, want only .lck", dirB, b)
+	}
+}
+
+// 同一版本的每个哈希目录都要安装，而不只是第一个
+func TestProcessMCreatorGradleHashDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	SetOffline(true)
+	defer SetOffline(false)
+
+	zipPath := filepath.Join(t.TempDir(), "gradle-8.7-bin.zip")
+	if err := os.WriteFile(zipPath, []byte(zipContent(t)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportGradle(context.Background(), zipPath, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	distsDir := t.TempDir()
+	makeDist(t, distsDir, "8.7", map[string]string{"gradle-8.7-bin.zip.lck": ""})
+	otherDir := filepath.Join(distsDir, "gradle-8.7-bin", "other")
+	if err := os.MkdirAll(otherDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(otherDir, "gradle-8.7-bin.zip.part"), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ProcessMCreatorGradle(context.Background(), distsDir, nil, ProcessOptions{Unpack: true, Jobs: 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("ProcessMCreatorGradle returned %d results, want 2", len(results))
+	}
+
+	entries, err := ListDists(distsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.State != DistOK {
+			t.Errorf("%s: state %s (%s), want %s", entry.Dir, entry.State, entry.Detail, DistOK)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package lib
+
+// 英语消息目录，键为简体中文原文
+var messagesEn = map[string]string{
+	"保存镜像源排名失败: %v":                           "failed to save mirror ranking: %v",
+	"HTTP状态码错误: %d":                           "unexpected HTTP status: %d",
+	"校验文件格式无效: %s":                            "invalid checksum file: %s",
+	"获取校验和失败: 镜像: %v, 官方: %v":                 "failed to fetch checksum: mirror: %v, official: %v",
+	"计算校验和失败: %v":                             "failed to compute checksum: %v",
+	"校验和不匹配: 期望 %s, 实际 %s":                    "checksum mismatch: expected %s, got %s",
+	"与distributionSha256Sum不一致: 期望 %s, 缓存 %s": "does not match distributionSha256Sum: expected %s, cached %s",
+	"读取配置文件失败: %v":                            "failed to read config file: %v",
+	"解析配置文件失败: %s: %v":                        "failed to parse config file: %s: %v",
+	"配置文件无效: %s: %v":                          "invalid config file: %s: %v",
+	"第%d个镜像源缺少name":                           "mirror #%d is missing a name",
+	"镜像源名称重复: %s":                             "duplicate mirror name: %s",
+	"镜像源 %s 的url缺少 {{version}} 占位符":           "url of mirror %s is missing the {{version}} placeholder",
+	"镜像源 %s 的url必须以 http:// 或 https:// 开头":    "url of mirror %s must start with http:// or https://",
+	"配置文件已存在: %s":                             "config file already exists: %s",
+	"# MCr_gradletools 配置文件\n":                "# MCr_gradletools config file\n",
+	"# 镜像源按顺序尝试，可增删、调整顺序，或设置 disabled: true 临时停用\n":                    "# Mirrors are tried in order. Add, remove or reorder them, or set disabled: true to turn one off\n",
+	"# url中的 {{version}} 会被替换为Gradle版本号，{{edition}} 会被替换为 bin 或 all\n": "# {{version}} in url is replaced by the Gradle version, {{edition}} by bin or all\n",
+	"生成配置文件失败: %v":                        "failed to generate config file: %v",
+	"创建配置目录失败: %v":                        "failed to create config directory: %v",
+	"写入配置文件失败: %v":                        "failed to write config file: %v",
+	"未知的镜像源: %s":                          "unknown mirror: %s",
+	"已下载部分无法在当前镜像续传，重新开始下载":               "The partial download cannot be resumed from this mirror, starting over",
+	"写入下载记录失败: %w":                        "failed to write download record: %w",
+	"读取下载文件失败: %w":                        "failed to read downloaded file: %w",
+	"文件大小不一致: 期望 %d 字节, 实际 %d 字节":         "size mismatch: expected %d bytes, got %d bytes",
+	"重命名下载文件失败: %w":                       "failed to rename downloaded file: %w",
+	"创建下载请求失败: %v":                        "failed to create download request: %v",
+	"下载失败: %w":                            "download failed: %w",
+	"从 %d 字节处继续下载\n":                      "Resuming download at byte %d\n",
+	"服务器不支持断点续传，重新开始下载":                   "The server does not support resuming, starting over",
+	"已下载部分与服务器文件不一致，重新下载":                 "partial download does not match the file on the server, downloading again",
+	"创建文件失败: %w":                          "failed to create file: %w",
+	"📥 下载进度":                              "📥 Downloading",
+	"✅ 下载完成\n":                            "✅ Download complete\n",
+	"缓存目录不存在: %s":                         "cache directory does not exist: %s",
+	"删除文件失败: %s, 错误: %v":                  "failed to delete file: %s, error: %v",
+	"已删除: %s\n":                           "Deleted: %s\n",
+	"清理缓存失败: %v":                          "failed to clear cache: %v",
+	"读取缓存目录失败: %v":                        "failed to read cache directory: %v",
+	"创建缓存目录失败: %v":                        "failed to create cache directory: %v",
+	"Gradle %s %s版 已存在于缓存目录中\n":           "Gradle %s (%s) is already in the cache\n",
+	"缓存中的Gradle %s %s版 校验失败，将重新下载: %v\n":  "Cached Gradle %s (%s) failed verification and will be downloaded again: %v\n",
+	"正在检查 %s 可用性...\n":                    "Checking availability of %s...\n",
+	"%s 不可用: %v\n":                        "%s is unavailable: %v\n",
+	"镜像不可用: %v":                           "mirror unavailable: %v",
+	"%s 可用（%s）\n":                         "%s is available (%s)\n",
+	"正在从镜像下载 %s %s版...\n":                 "Downloading %s (%s) from mirror...\n",
+	"正在校验SHA-256...":                      "Verifying SHA-256...",
+	"%s 下载的文件%v，已删除\n":                    "The file downloaded from %s failed verification (%v) and was deleted\n",
+	"Gradle %s %s版 下载完成\n":                "Gradle %s (%s) downloaded\n",
+	"没有可用于%s版的镜像源":                        "no mirror provides the %s edition",
+	"不支持的语言: %s（可选: %s、%s）":               "unsupported language: %s (available: %s, %s)",
+	"无法从文件名中提取Gradle版本信息: %s":             "cannot extract Gradle version from file name: %s",
+	"gradle目录不存在: %s":                     "gradle directory does not exist: %s",
+	"扫描Gradle目录失败: %v":                    "failed to scan Gradle directory: %v",
+	"删除.lck文件失败: %v":                      "failed to delete .lck file: %v",
+	"删除.part文件失败: %v":                     "failed to delete .part file: %v",
+	"下载Gradle失败: %v":                      "failed to download Gradle: %v",
+	"📋 复制进度":                              "📋 Copying",
+	"✅ 复制完成\n":                            "✅ Copy complete\n",
+	"复制文件失败: %v":                          "failed to copy file: %v",
+	"✅ 已复制: %s -> %s\n":                   "✅ Copied: %s -> %s\n",
+	"1. 删除临时文件...":                        "1. Deleting temporary files...",
+	"2. 下载并复制Gradle...":                   "2. Downloading and copying Gradle...",
+	"使用工作区指定的distributionSha256Sum: %s\n": "Using distributionSha256Sum from workspace: %s\n",
+	"3. 解压Gradle发行包...":                   "3. Unpacking Gradle distribution...",
+	"正在扫描MCreator Gradle目录...":            "Scanning MCreator Gradle directory...",
+	"未找到需要处理的Gradle文件":                    "No Gradle files need fixing",
+	"找到 %d 个需要处理的Gradle版本:\n":             "Found %d Gradle version(s) to fix:\n",
+	"\n[%d/%d] 处理Gradle %s %s版:\n":        "\n[%d/%d] Fixing Gradle %s (%s):\n",
+	"✅ Gradle %s %s版处理完成\n":               "✅ Gradle %s (%s) fixed\n",
+	"\n✅ 所有Gradle版本处理完成！共处理了 %d 个版本\n":    "\n✅ All Gradle versions fixed! %d version(s) processed\n",
+	"未知大小":                                "unknown size",
+	"不支持续传":                               "no resume support",
+	"支持续传":                                "resumable",
+	"所有%s版镜像源都不可用或下载失败:":                  "all mirrors for the %s edition are unavailable or failed:",
+	"[%s] 第 %d/%d 次尝试\n":                  "[%s] attempt %d/%d\n",
+	"[%s] 第 %d 次尝试失败: %v\n":               "[%s] attempt %d failed: %v\n",
+	"重试%d次后仍失败: %v":                       "still failing after %d attempts: %v",
+	"[%s] %v 后从 %d 字节处继续下载...\n":          "[%s] resuming at byte %[3]d in %[2]v...\n",
+	"[%s] %v 后重试...\n":                    "[%s] retrying in %v...\n",
+	"发行包不存在: %v":                          "distribution not found: %v",
+	"创建临时目录失败: %v":                        "failed to create temporary directory: %v",
+	"正在解压 %s ...\n":                       "Unpacking %s ...\n",
+	"解压失败: %v":                            "failed to unpack: %v",
+	"发行包结构无效，缺少 %s/lib 目录":                "invalid distribution layout, missing %s/lib directory",
+	"读取目标目录失败: %v":                        "failed to read target directory: %v",
+	"清理旧目录失败: %v":                         "failed to remove old directory: %v",
+	"移动解压目录失败: %v":                        "failed to move unpacked directory: %v",
+	"写入.ok标记文件失败: %v":                     "failed to write .ok marker file: %v",
+	"✅ 已解压到: %s\n":                        "✅ Unpacked to: %s\n",
+	"非法的文件路径: %s":                         "illegal file path: %s",
+	"无效的Gradle版本号: %s":                    "invalid Gradle version: %s",
+	"edition参数必须为 'bin' 或 'all'，当前为: %s":  "edition must be 'bin' or 'all', got: %s",
+	"读取Wrapper配置失败: %v":                   "failed to read wrapper properties: %v",
+	"解析Wrapper配置失败: %v":                   "failed to parse wrapper properties: %v",
+	"Wrapper配置中缺少distributionUrl":         "wrapper properties are missing distributionUrl",
+	"无效的distributionUrl: %v":              "invalid distributionUrl: %v",
+	"无效的distributionUrl: %s":              "invalid distributionUrl: %s",
+	"Gradle %s %s版 安装目录: %s\n":            "Gradle %s (%s) install directory: %s\n",
+	"Gradle %s %s版 已安装，无需处理\n":            "Gradle %s (%s) is already installed, nothing to do\n",
+	"创建安装目录失败: %v":                        "failed to create install directory: %v",
+	"一款Go语言编写的MCreator Gradle工具":          "A Gradle tool for MCreator written in Go",
+	"对所有确认提示自动回答\"是\"，用于脚本等非交互环境":         "Answer \"yes\" to all confirmation prompts, for scripts and other non-interactive use",
+	"输出格式: text (文本)、json (结构化结果，进度信息输出到标准错误) 或 jsonl (每行一个事件的JSON Lines)": "Output format: text, json (structured results; progress goes to stderr) or jsonl (one JSON event per line)",
+	"不支持的输出格式: %s":                        "unsupported output format: %s",
+	"测试所有镜像源的可用性和速度，并按速度排名":               "Test availability and speed of all mirrors and rank them",
+	"正在并发测试镜像源速度...":                      "Benchmarking mirrors concurrently...",
+	"\n排名\t镜像源\t首字节\t速度\t状态":              "\nRank\tMirror\tTTFB\tSpeed\tStatus",
+	"%d\t%s\t%v\t%s/s\t✓ 可用\n":            "%d\t%s\t%v\t%s/s\t✓ available\n",
+	"\n总计: %d个镜像源，%d个可用，%d个不可用\n":         "\nTotal: %d mirrors, %d available, %d unavailable\n",
+	"最快镜像源: %s（后续下载将优先使用）\n":              "Fastest mirror: %s (will be tried first on later downloads)\n",
+	"清理Gradle下载缓存":                        "Clear the Gradle download cache",
+	"仅列出缓存文件，不删除":                         "Only list cached files, do not delete",
+	"获取缓存文件列表失败: %v":                      "failed to list cached files: %v",
+	"缓存目录为空":                              "The cache directory is empty",
+	"缓存目录 (%s) 中的文件:\n":                   "Files in cache directory (%s):\n",
+	"总计: %d 个文件\n":                        "Total: %d file(s)\n",
+	"正在清理Gradle下载缓存...":                   "Clearing the Gradle download cache...",
+	"缓存目录为空，无需清理":                         "The cache directory is empty, nothing to clear",
+	"即将删除 %d 个缓存文件:\n":                    "About to delete %d cached file(s):\n",
+	"\n确认删除这些文件吗？(y/N): ":                 "\nDelete these files? (y/N): ",
+	"操作已中断":                               "Interrupted",
+	"操作已取消":                               "Cancelled",
+	"✅ 缓存清理完成":                            "✅ Cache cleared",
+	"管理配置文件（自定义镜像源等）":                     "Manage the config file (custom mirrors etc.)",
+	"以内置镜像源生成配置文件":                        "Generate a config file from the built-in mirrors",
+	"覆盖已存在的配置文件":                          "Overwrite an existing config file",
+	"✅ 已生成配置文件: %s\n":                     "✅ Config file generated: %s\n",
+	"显示配置文件路径":                            "Show the config file path",
+	"列出当前生效的镜像源":                          "List the mirrors currently in effect",
+	"启用":                                  "enabled",
+	"停用":                                  "disabled",
+	"下载指定版本的Gradle":                       "Download a specific Gradle version",
+	"Gradle版本号 (例如: 8.7、8.14.2、9.0-rc-1)": "Gradle version (e.g. 8.7, 8.14.2, 9.0-rc-1)",
+	"Gradle版本类型: bin (二进制版) 或 all (完整版)":  "Gradle edition: bin (binaries only) or all (complete)",
+	"仅使用指定的镜像源（镜像源名称或含 {{version}} 的URL模板，可多次指定）":                                                                   "Use only the given mirror (mirror name or URL template containing {{version}}, repeatable)",
+	"Gradle %s %s版下载安装成功！\n":                                                                                        "Gradle %s (%s) downloaded successfully!\n",
+	"自动处理MCreator的Gradle下载问题":                                                                                       "Automatically fix MCreator's Gradle download problems",
+	"MCreator Gradle目录路径":                                                                                           "Path of the MCreator Gradle directory",
+	"按Gradle Wrapper的方式直接解压安装，无需MCreator再次联网":                                                                       "Unpack and install the way the Gradle wrapper does, so MCreator needs no network access",
+	"读取distributionSha256Sum的MCreator工作区目录（可多次指定，默认为 %s 下的所有工作区）":                                                   "MCreator workspace to read distributionSha256Sum from (repeatable, defaults to all workspaces under %s)",
+	"处理MCreator Gradle失败: %v":                                                                                       "failed to fix MCreator Gradle: %v",
+	"按distributionUrl预先安装Gradle，无需先让MCreator构建失败":                                                                   "Pre-install Gradle for a distributionUrl without a failed MCreator build first",
+	"gradle-wrapper.properties中的distributionUrl (例如: https://services.gradle.org/distributions/gradle-8.7-bin.zip)": "distributionUrl from gradle-wrapper.properties (e.g. https://services.gradle.org/distributions/gradle-8.7-bin.zip)",
+	"按Gradle Wrapper的方式直接解压安装":                                                                                      "Unpack and install the way the Gradle wrapper does",
+	"发行包的SHA-256校验和（gradle-wrapper.properties中的distributionSha256Sum）":                                              "SHA-256 checksum of the distribution (distributionSha256Sum in gradle-wrapper.properties)",
+	"安装Gradle失败: %v": "failed to install Gradle: %v",
+	"✅ Gradle安装完成":   "✅ Gradle installed",
+	"读取MCreator工作区的Wrapper配置，检查所需Gradle版本是否已安装": "Read the wrapper properties of MCreator workspaces and check whether the required Gradle versions are installed",
+	"<工作区目录>...":           "<workspace dir>...",
+	"请指定至少一个MCreator工作区目录": "please specify at least one MCreator workspace directory",
+	"  Gradle版本: %s %s版\n": "  Gradle version: %s (%s)\n",
+	"  安装目录: %s\n":         "  Install directory: %s\n",
+	"  ✅ 已安装":              "  ✅ Installed",
+	"  📦 未安装，缓存中已有发行包（可使用 install 命令安装）":           "  📦 Not installed, but the distribution is cached (use the install command)",
+	"  ⚠️ 未安装，缓存中的发行包与distributionSha256Sum不一致":    "  ⚠️ Not installed, and the cached distribution does not match distributionSha256Sum",
+	"  ⚠️ 未安装，缓存中也没有发行包":                           "  ⚠️ Not installed, and not in the cache either",
+	"\n有 %d 个Gradle版本尚未下载:\n":                      "\n%d Gradle version(s) not downloaded yet:\n",
+	"  %d. Gradle %s %s版\n":                        "  %d. Gradle %s (%s)\n",
+	"\n是否现在预先下载？(y/N): ":                           "\nDownload them now? (y/N): ",
+	"✅ 预先下载完成":                                     "✅ Prefetch complete",
+	"显示程序版本信息":                                     "Show version information",
+	"%s 版本信息\n":                                    "%s version information\n",
+	"版本: %s\n":                                     "Version: %s\n",
+	"构建日期: %s\n":                                   "Build date: %s\n",
+	"Go版本: %s\n":                                   "Go version: %s\n",
+	"项目仓库: %s\n":                                   "Repository: %s\n",
+	"\n一款专为MCreator设计的Gradle管理工具":                  "\nA Gradle management tool designed for MCreator",
+	"作者: CreateCN":                                 "Author: CreateCN",
+	"MCr_gradletools - MCreator Gradle管理工具":        "MCr_gradletools - Gradle management tool for MCreator",
+	"使用 '--help' 查看可用命令":                           "Use '--help' to see available commands",
+	"可用命令:":                                        "Available commands:",
+	"  check-mirrors - 测试镜像源可用性和速度":                "  check-mirrors - Test mirror availability and speed",
+	"  clear-cache   - 清理Gradle下载缓存":               "  clear-cache   - Clear the Gradle download cache",
+	"  config        - 管理配置文件（自定义镜像源等）":            "  config        - Manage the config file (custom mirrors etc.)",
+	"  download      - 下载指定版本的Gradle":              "  download      - Download a specific Gradle version",
+	"  gradle        - 自动处理MCreator的Gradle下载问题":    "  gradle        - Automatically fix MCreator's Gradle download problems",
+	"  install       - 按distributionUrl预先安装Gradle": "  install       - Pre-install Gradle for a distributionUrl",
+	"  workspace     - 检查MCreator工作区所需的Gradle版本":   "  workspace     - Check the Gradle versions MCreator workspaces need",
+	"  version       - 显示程序版本信息":                   "  version       - Show version information",
+	"界面语言: zh-CN 或 en（默认根据 LANG/LC_ALL 环境变量）":      "Interface language: zh-CN or en (defaults to the LANG/LC_ALL environment variables)",
+	"JSON输出模式下无法交互确认，请同时指定 --yes":                  "cannot ask for confirmation in JSON output mode, please also pass --yes",
+	"读取缓存索引失败: %v":                                 "failed to read cache index: %v",
+	"缓存索引格式无效: %v":                                 "invalid cache index: %v",
+	"保存缓存索引失败: %v":                                 "failed to save cache index: %v",
+	"正在校验 %s ...\n":                                "Verifying %s ...\n",
+	"缺少校验和记录: %s":                                  "no checksum recorded for %s",
+	"查看、校验、导出和导入Gradle下载缓存":                        "Inspect, verify, export and import the Gradle download cache",
+	"列出缓存的Gradle发行包及其来源、大小和使用时间":                   "List cached Gradle distributions with their source, size and usage times",
+	"版本\t大小\t镜像源\t下载时间\t最后使用":                      "Version\tSize\tMirror\tDownloaded\tLast used",
+	"%s %s版\t%s\t%s\t%s\t%s\n":                     "%s (%s)\t%s\t%s\t%s\t%s\n",
+	"重新校验缓存中的每个发行包，找出损坏、丢失和多余的文件":                  "Re-verify every cached distribution and find corrupt, missing and untracked files",
+	"删除损坏和多余的文件，并从索引中移除失效的记录":                      "Delete corrupt and untracked files and drop stale index entries",
+	"校验缓存失败: %v":                                   "failed to verify cache: %v",
+	"（已清理）":                                        "(cleaned up)",
+	"总计: %d 个文件，%d 个完整，%d 个问题未处理\n":                "Total: %d file(s), %d intact, %d unresolved problem(s)\n",
+	"缓存中存在问题，可使用 --remove 清理":                      "the cache has problems, use --remove to clean them up",
+	"  cache         - 查看、校验、导出和导入Gradle下载缓存":      "  cache         - Inspect, verify, export and import the Gradle download cache",
+	"匹配筛选条件":                                       "matches the filter",
+	"超过 %s 未使用":                                    "unused for more than %s",
+	"不在最新的 %d 个版本中":                                "not among the latest %d versions",
+	"缓存超过 %s 上限":                                   "cache exceeds the %s limit",
+	"无效的时长: %s":                                    "invalid duration: %s",
+	"无效的大小: %s":                                    "invalid size: %s",
+	"%d天":                                          "%d days",
+	"只清理指定的Gradle版本":                               "Only clear the given Gradle version",
+	"只清理指定的版本类型: bin 或 all":                        "Only clear the given edition: bin or all",
+	"清理超过指定时长未使用的发行包 (例如: 30d、2w、12h)":             "Clear distributions unused for longer than this (e.g. 30d, 2w, 12h)",
+	"每种版本类型只保留版本号最新的N个发行包":                         "Keep only the N newest versions of each edition",
+	"缓存总大小上限 (例如: 2GB)，超出时优先清理最久未使用的发行包":           "Maximum total cache size (e.g. 2GB); least recently used distributions are cleared first",
+	"按策略清理时保留这些MCreator工作区使用的Gradle版本（可多次指定，默认为 %s 下的所有工作区）": "Keep the Gradle versions used by these MCreator workspaces when clearing by policy (repeatable, defaults to all workspaces under %s)",
+	"只显示将要删除的文件和可释放的空间，不实际删除":                                "Only show what would be deleted and how much space would be freed",
+	"可释放空间: %s\n":                       "Space to be freed: %s\n",
+	"预演模式，未删除任何文件":                      "Dry run, no files were deleted",
+	"没有符合条件的缓存文件，无需清理":                  "No cached files match, nothing to clear",
+	"存在.lck锁文件":                         "a .lck lock file exists",
+	"ZIP文件无效: %v":                       "invalid ZIP file: %v",
+	"发行包未解压":                            "distribution not unpacked",
+	"下载未完成":                             "download incomplete",
+	"缺少发行包":                             "distribution missing",
+	"\n[%d/%d] 修复Gradle %s %s版:\n":      "\n[%d/%d] Repairing Gradle %s (%s):\n",
+	"❌ Gradle %s %s版修复失败: %v\n":         "❌ Failed to repair Gradle %s (%s): %v\n",
+	"✅ Gradle %s %s版修复完成\n":             "✅ Gradle %s (%s) repaired\n",
+	"管理MCreator的Gradle Wrapper dists目录": "Manage the Gradle Wrapper dists directory used by MCreator",
+	"列出已安装的Gradle版本及其状态和大小":             "List installed Gradle versions with their state and size",
+	"dists目录中没有Gradle发行包":               "No Gradle distributions in the dists directory",
+	"版本\t状态\t大小\t目录\t说明":                "VERSION\tSTATE\tSIZE\tDIRECTORY\tDETAIL",
+	"\n总计: %d 个发行包，%s\n":                "\nTotal: %d distributions, %s\n",
+	"删除没有被任何已知工作区使用的Gradle版本":           "Remove Gradle versions not used by any known workspace",
+	"仍在使用的MCreator工作区目录（可多次指定，默认为 %s 下的所有工作区）": "MCreator workspace directory still in use (repeatable, defaults to all workspaces under %s)",
+	"未找到任何MCreator工作区，请使用 --workspace 指定":      "No MCreator workspaces found, specify them with --workspace",
+	"所有Gradle版本都在使用中，无需清理":                     "All Gradle versions are in use, nothing to clean up",
+	"以下 %d 个Gradle版本没有被工作区使用:\n":               "The following %d Gradle versions are not used by any workspace:\n",
+	"✅ 清理完成": "✅ Cleanup complete",
+	"重新下载并复制状态异常的Gradle版本":                                "Re-download and copy Gradle versions in a broken state",
+	"没有需要修复的Gradle版本":                                     "No Gradle versions need repair",
+	"找到 %d 个需要修复的Gradle版本:\n":                             "Found %d Gradle versions that need repair:\n",
+	"%d 个Gradle版本修复失败":                                    "%d Gradle versions failed to repair",
+	"  dists         - 管理MCreator的Gradle Wrapper dists目录": "  dists         - Manage the Gradle Wrapper dists directory used by MCreator",
+	"\n处理结果:\n":                                           "\nResults:\n",
+	"  ✅ Gradle %s %s版\n":                                 "  ✅ Gradle %s (%s)\n",
+	"  ❌ Gradle %s %s版: %s\n":                             "  ❌ Gradle %s (%s): %s\n",
+	"  ⏭️ Gradle %s %s版: 未处理\n":                           "  ⏭️ Gradle %s (%s): not processed\n",
+	"❌ Gradle %s %s版处理失败: %v\n":                           "❌ Failed to process Gradle %s (%s): %v\n",
+	"%d 个Gradle版本处理失败":                                    "%d Gradle versions failed",
+	"同时处理的Gradle版本数量":                                     "Number of Gradle versions to process concurrently",
+	"正在被 %s 使用":                                           "in use by %s",
+	"未知进程":                                                "unknown process",
+	"⚠️ 临时文件正在被 %s 使用，已指定 --force，仍然删除\n":                 "⚠️ Temporary files are in use by %s, deleting anyway because --force was given\n",
+	"临时文件正在被 %s 使用，等待其释放（最多 %s）...\n":                     "Temporary files are in use by %s, waiting for release (up to %s)...\n",
+	"临时文件已释放":                                             "Temporary files released",
+	"Gradle %s %s版的临时文件正在被 %s 使用，可能仍在下载中；请等待下载结束或关闭MCreator后重试，或使用 --force 强制处理": "temporary files of Gradle %s (%s) are in use by %s and may still be downloading; wait for the download to finish or close MCreator and retry, or use --force",
+	"即使.lck/.part文件正被MCreator或Gradle进程使用也强制删除":                                   "Delete .lck/.part files even if an MCreator or Gradle process is using them",
+	"临时文件正被其他进程使用时，等待其释放的最长时间（如 5m），默认不等待":                                       "Maximum time to wait for temporary files in use by another process to be released (e.g. 5m); no wait by default",
+	"镜像源 %s 的proxy无效: %v": "invalid proxy for mirror %s: %v",
+	"# 需要代理时可设置 proxy: http://127.0.0.1:7890（也支持 socks5://），\n": "# Set proxy: http://127.0.0.1:7890 if you need a proxy (socks5:// is also supported);\n",
+	"# 镜像源中的 proxy 只对该镜像生效，direct 表示直接连接\n":                     "# a proxy on a mirror applies to that mirror only, and direct means no proxy\n",
+	"通过代理 %s 连接\n": "Connecting through proxy %s\n",
+	"无效的代理地址: %s":  "invalid proxy address: %s",
+	"不支持的代理协议: %s（支持 http、https、socks5、socks5h）": "unsupported proxy scheme: %s (http, https, socks5 and socks5h are supported)",
+	"所有网络请求使用的代理，如 http://127.0.0.1:7890 或 socks5://127.0.0.1:1080，direct 表示不使用代理（默认读取配置文件和 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 环境变量）": "Proxy for all network requests, e.g. http://127.0.0.1:7890 or socks5://127.0.0.1:1080; direct disables proxies (defaults to the config file and the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables)",
+	"     代理: %s\n":         "     Proxy: %s\n",
+	"读取CA证书失败: %v":          "failed to read CA certificate: %v",
+	"CA证书文件中没有有效的PEM证书: %s": "no valid PEM certificates in CA file: %s",
+	"客户端证书和私钥需要同时指定":        "the client certificate and key must be specified together",
+	"加载客户端证书失败: %v":         "failed to load client certificate: %v",
+	"%v（服务器证书不受信任，如为公司内部CA签发，可用 --ca-cert 或配置文件中的 tls.ca_certs 指定CA证书）":                       "%v (the server certificate is not trusted; if it is issued by a company CA, specify the CA certificate with --ca-cert or tls.ca_certs in the config file)",
+	"额外信任的CA证书文件（PEM格式，可多次指定），用于使用公司内部CA签发证书的镜像源":                                             "Additional trusted CA certificate file (PEM, repeatable), for mirrors with certificates issued by a company CA",
+	"mTLS客户端证书文件（PEM格式），需同时指定 --client-key":                                                   "mTLS client certificate file (PEM), requires --client-key",
+	"mTLS客户端证书的私钥文件（PEM格式）":                                                                   "Private key file for the mTLS client certificate (PEM)",
+	"不校验服务器的TLS证书（不安全，仅用于排查问题）":                                                               "Do not verify server TLS certificates (insecure, for troubleshooting only)",
+	"⚠️⚠️⚠️ 警告: 已关闭TLS证书校验！连接可能被窃听或篡改，下载的发行包和校验和都可能被替换。请勿在日常使用中开启 --insecure 或 tls.insecure。": "⚠️⚠️⚠️ WARNING: TLS certificate verification is disabled! Connections can be intercepted or tampered with, and both the downloaded distributions and their checksums could be replaced. Do not use --insecure or tls.insecure for everyday use.",
+	"basic认证需要同时设置username_env和password_env，或设置netrc: true":                                   "basic auth requires both username_env and password_env, or netrc: true",
+	"bearer认证需要设置token_env，或设置netrc: true":                                                    "bearer auth requires token_env, or netrc: true",
+	"不支持的认证方式: %s（支持 basic、bearer）":                                                           "unsupported auth type: %s (basic and bearer are supported)",
+	"认证所需的环境变量 %s 未设置":                                                                        "environment variable %s required for authentication is not set",
+	"认证失败（HTTP %d）: 镜像源 %s 需要认证，请在配置文件中为其设置auth":                                              "authentication failed (HTTP %d): mirror %s requires authentication, configure auth for it in the config file",
+	"认证失败（HTTP %d）: 镜像源 %s 拒绝了凭据 %s，请检查环境变量或~/.netrc中的用户名、密码或令牌":                              "authentication failed (HTTP %d): mirror %s rejected the credentials from %s, check the username, password or token in the environment variables or ~/.netrc",
+	"读取.netrc失败: %v":         "failed to read .netrc: %v",
+	".netrc中没有主机 %s 的记录: %s": "no entry for host %s in .netrc: %s",
+	"镜像源 %s 的auth无效: %v":     "invalid auth for mirror %s: %v",
+	"     认证: %s\n":          "     Auth: %s\n",
+	"离线模式下无法重新下载，缓存中的Gradle %s %s版 校验失败: %v":    "cannot re-download in offline mode, cached Gradle %s %s failed verification: %v",
+	"离线模式: 缓存中没有Gradle %s %s版，请先使用 import 命令导入": "offline mode: Gradle %s %s is not in the cache, import it first with the import command",
+	"离线模式下不访问网络: %s":                            "network access is disabled in offline mode: %s",
+	"不是有效的ZIP文件: %v":                            "not a valid ZIP file: %v",
+	"发行包结构无效，根目录不唯一: %s, %s":                    "invalid distribution layout, more than one top-level directory: %s, %s",
+	"发行包结构无效，根目录不是 gradle-<版本号>: %s":            "invalid distribution layout, top-level directory is not gradle-<version>: %s",
+	"文件名与发行包内容不一致: 文件名为 %s %s版，内容为 %s %s版":      "file name does not match the distribution contents: name says %s %s, contents are %s %s",
+	"读取导入文件失败: %v":                              "failed to read import source: %v",
+	"读取导入目录失败: %v":                              "failed to read import directory: %v",
+	"目录中没有ZIP文件: %s":                            "no ZIP files in directory: %s",
+	"❌ %s 导入失败: %s\n":                           "❌ failed to import %s: %s\n",
+	"%d 个发行包导入失败":                               "%d distribution(s) failed to import",
+	"正在检查 %s ...\n":                             "Checking %s ...\n",
+	"校验和文件中没有有效的SHA-256: %s":                    "no valid SHA-256 in checksum file: %s",
+	"已按 %s 校验SHA-256\n":                         "SHA-256 verified against %s\n",
+	"缓存中已有不同的Gradle %s %s版（SHA-256 %s），如需替换请先使用 clear-cache 删除": "the cache already holds a different Gradle %s %s (SHA-256 %s); remove it with clear-cache first to replace it",
+	"✅ 已导入 Gradle %s %s版\n": "✅ Imported Gradle %s %s\n",
+	"不是普通文件":                "not a regular file",
+	"离线模式，只使用缓存中的发行包，不访问网络": "Offline mode: use only distributions from the cache and never access the network",
+	"从本地ZIP文件或目录导入Gradle发行包到缓存，用于无法联网的电脑": "Import Gradle distributions from a local ZIP file or directory into the cache, for machines without network access",
+	"<ZIP文件或目录>":            "<zip-file-or-directory>",
+	"请指定一个ZIP文件或包含ZIP文件的目录": "specify one ZIP file or a directory containing ZIP files",
+	"导入失败: %v":              "import failed: %v",
+	"总计: %d 个文件，%d 个已导入或已存在，%d 个失败\n":          "Total: %d files, %d imported or already cached, %d failed\n",
+	"  import        - 从本地ZIP文件或目录导入Gradle发行包": "  import        - Import Gradle distributions from a local ZIP file or directory",
+	"不支持的离线包格式版本: %d，请升级本程序":                   "unsupported bundle format version: %d, please upgrade this program",
+	"离线包清单中没有发行包":                              "the bundle manifest lists no distributions",
+	"离线包清单中的文件名无效: %s":                         "invalid file name in bundle manifest: %s",
+	"离线包清单中 %s 的校验和无效: %s":                     "invalid checksum for %s in bundle manifest: %s",
+	"离线包清单中的文件重复: %s":                          "duplicate file in bundle manifest: %s",
+	"缓存中没有Gradle %s，请先下载或导入":                   "Gradle %s is not in the cache, download or import it first",
+	"缓存中没有可导出的发行包":                             "no cached distributions to export",
+	"缓存中的Gradle %s %s版 校验失败: %v":               "cached Gradle %s %s failed verification: %v",
+	"生成离线包清单失败: %v":                            "failed to create bundle manifest: %v",
+	"写入离线包失败: %v":                              "failed to write bundle: %v",
+	"✅ 已导出: %s\n":                              "✅ Exported: %s\n",
+	"打开离线包失败: %v":                              "failed to open bundle: %v",
+	"读取离线包失败: %v":                              "failed to read bundle: %v",
+	"⚠️ 跳过清单中没有的文件: %s\n":                      "⚠️ Skipping file not listed in the manifest: %s\n",
+	"离线包中缺少该文件":                                "file is missing from the bundle",
+	"不是有效的离线包: 缺少%s":                           "not a valid bundle: missing %s",
+	"离线包清单格式无效: %v":                            "invalid bundle manifest: %v",
+	"安装到dists目录失败: %v":                         "failed to install into the dists directory: %v",
+	"将缓存中的发行包打包为离线包（含校验和清单），分发给无法联网的电脑":                        "pack cached distributions into an offline bundle (with a checksum manifest) for machines without network access",
+	"要导出的Gradle版本号，以逗号分隔（例如: 8.7,8.14.2），默认导出全部缓存":             "Gradle versions to export, comma-separated (e.g. 8.7,8.14.2); exports the whole cache by default",
+	"只导出指定的版本类型: bin 或 all":                                    "export only the given edition: bin or all",
+	"离线包的保存路径 (例如: bundle.tar)":                                "path of the bundle to write (e.g. bundle.tar)",
+	"导出离线包失败: %v":                                              "failed to export bundle: %v",
+	"✅ 已导出 %d 个发行包到 %s（%s）\n":                                  "✅ Exported %d distribution(s) to %s (%s)\n",
+	"导入 cache export 生成的离线包，按清单校验后放入缓存，可同时安装到MCreator的dists目录": "Import a bundle created by cache export, verify it against its manifest and add it to the cache, optionally installing into MCreator dists",
+	"<离线包>": "<bundle>",
+	"同时安装到MCreator的dists目录（按官方distributionUrl计算安装目录）": "Also install into the MCreator dists directory (install directory derived from the official distributionUrl)",
+	"安装到dists目录时按Gradle Wrapper的方式直接解压":               "unpack like the Gradle Wrapper when installing into dists",
+	"请指定一个离线包":                   "specify one bundle",
+	"导入离线包失败: %v":                "failed to import bundle: %v",
+	"移动下载文件到缓存目录失败: %v":          "failed to move the downloaded file into the cache: %v",
+	"❌ Gradle %s %s版 下载失败: %v\n": "❌ Gradle %s %s download failed: %v\n",
+	"%d 个Gradle版本下载失败":           "%d Gradle versions failed to download",
+	"%s 中主机 %s 的记录没有令牌":          "the entry for host %[2]s in %[1]s has no token",
+	"%s 中主机 %s 的记录没有用户名和密码":      "the entry for host %[2]s in %[1]s has no login or password",
+	"⏭️ 跳过Gradle %s %s版: %s（可使用 --force 强制修复或 --wait 等待）\n": "⏭️ Skipping Gradle %s %s: %s (use --force to repair anyway or --wait to wait)\n",
+}
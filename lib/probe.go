@@ -0,0 +1,170 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// 镜像探测请求的超时时间
+const probeTimeout = 10 * time.Second
+
+// 镜像探测结果，供下载步骤复用
+type probeResult struct {
+	ContentLength int64  // 文件总大小，未知时为-1
+	ETag          string // 服务器返回的ETag，用于续传校验
+	AcceptRanges  bool   // 服务器是否支持Range请求
+}
+
+// .partial文件的元数据，记录开始下载时服务器上文件的信息
+type partialMeta struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag,omitempty"`
+	Size int64  `json:"size"`
+}
+
+// 探测镜像上的文件是否存在，不下载文件内容
+// 优先使用HEAD请求，服务器不支持HEAD时回退为 Range: bytes=0-0 的GET请求
+func probeMirror(ctx context.Context, mirror Mirror, url string) (*probeResult, error) {
+	client := newHTTPClient(mirror, probeTimeout)
+
+	result, err := probeWithHead(ctx, client, url)
+	if err == nil {
+		return result, nil
+	}
+
+	// 404说明文件确实不存在，无需再尝试GET
+	if statusErr, ok := err.(*httpStatusError); ok && statusErr.code == http.StatusNotFound {
+		return nil, err
+	}
+	return probeWithRange(ctx, client, url)
+}
+
+// 使用HEAD请求探测
+func probeWithHead(ctx context.Context, client *http.Client, url string) (*probeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	setRequestHeaders(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpStatusError{code: resp.StatusCode}
+	}
+
+	return &probeResult{
+		ContentLength: resp.ContentLength,
+		ETag:          resp.Header.Get("ETag"),
+		AcceptRanges:  strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes"),
+	}, nil
+}
+
+// 使用只请求第一个字节的GET请求探测
+func probeWithRange(ctx context.Context, client *http.Client, url string) (*probeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	setRequestHeaders(req)
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// 不读取响应内容，服务器忽略Range时直接关闭连接
+	defer resp.Body.Close()
+
+	result := &probeResult{
+		ContentLength: -1,
+		ETag:          resp.Header.Get("ETag"),
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		result.AcceptRanges = true
+		if size, ok := parseContentRangeTotal(resp.Header.Get("Content-Range")); ok {
+			result.ContentLength = size
+		}
+	case http.StatusOK:
+		// 服务器不支持Range，返回了完整文件
+		result.ContentLength = resp.ContentLength
+	default:
+		return nil, &httpStatusError{code: resp.StatusCode}
+	}
+	return result, nil
+}
+
+// 添加常见的HTTP请求头，模拟浏览器行为
+func setRequestHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Accept", "*/*")
+	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+}
+
+// 获取.partial文件元数据的路径
+func partialMetaPath(partialPath string) string {
+	return partialPath + ".json"
+}
+
+// 读取.partial文件的元数据，不存在或损坏时返回nil
+func loadPartialMeta(partialPath string) *partialMeta {
+	data, err := os.ReadFile(partialMetaPath(partialPath))
+	if err != nil {
+		return nil
+	}
+	var meta partialMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// 保存.partial文件的元数据
+func savePartialMeta(partialPath string, meta partialMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(partialMetaPath(partialPath), data, 0644)
+}
+
+// 判断已下载的部分能否在当前镜像上续传
+func canResume(meta *partialMeta, offset int64, probe *probeResult) bool {
+	if !probe.AcceptRanges {
+		return false
+	}
+	if probe.ContentLength >= 0 {
+		if offset > probe.ContentLength {
+			return false
+		}
+		// 不同镜像的ETag不同，但同一发行包的大小必然一致
+		if meta != nil && meta.Size >= 0 && meta.Size != probe.ContentLength {
+			return false
+		}
+	}
+	return true
+}
+
+// 描述探测结果，便于在日志中显示
+func (p *probeResult) String() string {
+	size := T("未知大小")
+	if p.ContentLength >= 0 {
+		size = FormatBytes(p.ContentLength)
+	}
+	resume := T("不支持续传")
+	if p.AcceptRanges {
+		resume = T("支持续传")
+	}
+	return fmt.Sprintf("%s, %s", size, resume)
+}
@@ -0,0 +1,220 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePruneOptions 按条件清理缓存的选项，零值表示不限制
+type CachePruneOptions struct {
+	Version    string        // 只清理指定的Gradle版本（8.7与8.7.0视为同一版本）
+	Edition    string        // 只清理指定的版本类型 (bin/all)
+	OlderThan  time.Duration // 清理超过该时长未使用的发行包
+	KeepLatest int           // 每种版本类型只保留版本号最新的N个
+	MaxSize    int64         // 缓存总大小上限（字节），超出时按最后使用时间从旧到新清理
+
+	// 受保护的发行包（<版本号>-<版本类型>，如工作区正在使用的版本），
+	// 不会因OlderThan、KeepLatest或MaxSize被清理
+	Protected []string
+}
+
+// 是否设置了清理策略（OlderThan、KeepLatest或MaxSize）
+func (o CachePruneOptions) hasPolicy() bool {
+	return o.OlderThan > 0 || o.KeepLatest > 0 || o.MaxSize > 0
+}
+
+// PruneCandidate 将被清理的发行包及原因
+type PruneCandidate struct {
+	CacheEntry
+	Reason string `json:"reason"`
+}
+
+// PlanCachePrune 根据选项计算需要清理的发行包，不删除任何文件
+// 只指定Version/Edition时清理所有匹配的发行包；设置了清理策略时只清理匹配且满足策略的发行包
+func PlanCachePrune(opts CachePruneOptions) ([]PruneCandidate, error) {
+	var version GradleVersion
+	if opts.Version != "" {
+		var err error
+		if version, err = ParseGradleVersion(opts.Version); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := ListCacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	protected := make(map[string]bool)
+	for _, key := range opts.Protected {
+		protected[key] = true
+	}
+
+	// 按版本号和版本类型筛选
+	var matched []CacheEntry
+	for _, entry := range entries {
+		if opts.Version != "" {
+			if v, err := ParseGradleVersion(entry.Version); err != nil || v.Compare(version) != 0 {
+				continue
+			}
+		}
+		if opts.Edition != "" && entry.Edition != opts.Edition {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+
+	if !opts.hasPolicy() {
+		candidates := make([]PruneCandidate, 0, len(matched))
+		for _, entry := range matched {
+			candidates = append(candidates, PruneCandidate{CacheEntry: entry, Reason: T("匹配筛选条件")})
+		}
+		return candidates, nil
+	}
+
+	reasons := make(map[string]string)
+	now := time.Now()
+
+	if opts.OlderThan > 0 {
+		for _, entry := range matched {
+			if now.Sub(entry.LastUsedAt) > opts.OlderThan {
+				reasons[entry.File] = T("超过 %s 未使用", formatAge(opts.OlderThan))
+			}
+		}
+	}
+
+	if opts.KeepLatest > 0 {
+		byEdition := make(map[string][]CacheEntry)
+		for _, entry := range matched {
+			byEdition[entry.Edition] = append(byEdition[entry.Edition], entry)
+		}
+		for _, list := range byEdition {
+			// 按版本号从新到旧排列
+			sort.SliceStable(list, func(i, j int) bool {
+				vi, _ := ParseGradleVersion(list[i].Version)
+				vj, _ := ParseGradleVersion(list[j].Version)
+				return vi.Compare(vj) > 0
+			})
+			for _, entry := range list[min(opts.KeepLatest, len(list)):] {
+				if _, ok := reasons[entry.File]; !ok {
+					reasons[entry.File] = T("不在最新的 %d 个版本中", opts.KeepLatest)
+				}
+			}
+		}
+	}
+
+	// 受保护的发行包不参与按策略清理
+	for _, entry := range matched {
+		if protected[entry.Version+"-"+entry.Edition] {
+			delete(reasons, entry.File)
+		}
+	}
+
+	if opts.MaxSize > 0 {
+		// 计算清理后的总大小，超出上限时按最后使用时间从旧到新继续清理
+		var total int64
+		for _, entry := range entries {
+			if _, ok := reasons[entry.File]; !ok {
+				total += entry.Size
+			}
+		}
+
+		lru := append([]CacheEntry(nil), matched...)
+		sort.SliceStable(lru, func(i, j int) bool {
+			return lru[i].LastUsedAt.Before(lru[j].LastUsedAt)
+		})
+		for _, entry := range lru {
+			if total <= opts.MaxSize {
+				break
+			}
+			if _, ok := reasons[entry.File]; ok || protected[entry.Version+"-"+entry.Edition] {
+				continue
+			}
+			reasons[entry.File] = T("缓存超过 %s 上限", FormatBytes(opts.MaxSize))
+			total -= entry.Size
+		}
+	}
+
+	var candidates []PruneCandidate
+	for _, entry := range matched {
+		if reason, ok := reasons[entry.File]; ok {
+			candidates = append(candidates, PruneCandidate{CacheEntry: entry, Reason: reason})
+		}
+	}
+	return candidates, nil
+}
+
+// PruneCache 删除PlanCachePrune选出的发行包及其索引记录，返回已删除的文件名
+func PruneCache(candidates []PruneCandidate, reporter Reporter) ([]string, error) {
+	var deleted []string
+	for _, candidate := range candidates {
+		path := filepath.Join(GetCacheDir(), candidate.File)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return deleted, fmt.Errorf(T("删除文件失败: %s, 错误: %v"), path, err)
+		}
+		if err := forgetCachedGradle(path); err != nil {
+			return deleted, err
+		}
+		report(reporter, Event{Type: EventFileDeleted, Path: path, Message: T("已删除: %s\n", candidate.File)})
+		deleted = append(deleted, candidate.File)
+	}
+	return deleted, nil
+}
+
+// ParseAge 解析时长，支持 30d、2w 以及Go的时长格式（如 12h、90m）
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf(T("无效的时长: %s"), s)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf(T("无效的时长: %s"), s)
+	}
+	return d, nil
+}
+
+// ParseSize 解析大小，如 2GB、500MB、1.5G，单位按1024进制计算，无单位时为字节
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(strings.ToUpper(s))
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.TrimSpace(s[i:])
+
+	multipliers := map[string]int64{
+		"": 1, "B": 1,
+		"K": 1 << 10, "KB": 1 << 10, "KIB": 1 << 10,
+		"M": 1 << 20, "MB": 1 << 20, "MIB": 1 << 20,
+		"G": 1 << 30, "GB": 1 << 30, "GIB": 1 << 30,
+		"T": 1 << 40, "TB": 1 << 40, "TIB": 1 << 40,
+	}
+	multiplier, ok := multipliers[unit]
+	n, err := strconv.ParseFloat(number, 64)
+	if !ok || err != nil || n < 0 {
+		return 0, fmt.Errorf(T("无效的大小: %s"), s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// 以天或小时描述时长
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return T("%d天", int(d/(24*time.Hour)))
+	}
+	return d.String()
+}
@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{s: "30d", want: 30 * 24 * time.Hour},
+		{s: "2w", want: 14 * 24 * time.Hour},
+		{s: "1.5d", want: 36 * time.Hour},
+		{s: " 7D ", want: 7 * 24 * time.Hour},
+		{s: "12h", want: 12 * time.Hour},
+		{s: "90m", want: 90 * time.Minute},
+		{s: "0d", want: 0},
+		{s: "", wantErr: true},
+		{s: "d", wantErr: true},
+		{s: "-1d", wantErr: true},
+		{s: "-2h", wantErr: true},
+		{s: "30", wantErr: true},
+		{s: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAge(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    int64
+		wantErr bool
+	}{
+		{s: "1024", want: 1024},
+		{s: "100B", want: 100},
+		{s: "2KB", want: 2 << 10},
+		{s: "500MB", want: 500 << 20},
+		{s: "500mb", want: 500 << 20},
+		{s: "1.5G", want: 3 << 29},
+		{s: "2 GiB", want: 2 << 30},
+		{s: "1TB", want: 1 << 40},
+		{s: "", wantErr: true},
+		{s: "GB", wantErr: true},
+		{s: "10XB", wantErr: true},
+		{s: "-1GB", wantErr: true},
+		{s: "1.2.3MB", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSize(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+// 使用临时目录作为用户主目录，写入只有索引记录的缓存
+func setupCacheIndex(t *testing.T, entries []CacheEntry) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := saveCacheIndex(&cacheIndex{Entries: entries}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanCachePrune(t *testing.T) {
+	now := time.Now()
+	entry := func(version, edition string, size int64, lastUsed time.Duration) CacheEntry {
+		return CacheEntry{
+			Version:    version,
+			Edition:    edition,
+			File:       "gradle-" + version + "-" + edition + ".zip",
+			Size:       size,
+			LastUsedAt: now.Add(-lastUsed),
+		}
+	}
+	day := 24 * time.Hour
+	setupCacheIndex(t, []CacheEntry{
+		entry("8.5", "bin", 100, 60*day),
+		entry("8.6", "bin", 100, 10*day),
+		entry("8.7", "bin", 100, day),
+		entry("8.7", "all", 200, 40*day),
+		entry("8.10", "bin", 100, 2*time.Hour),
+	})
+
+	tests := []struct {
+		name string
+		opts CachePruneOptions
+		want []string // 将被清理的 <版本号>-<版本类型>
+	}{
+		{"no filter", CachePruneOptions{}, []string{"8.5-bin", "8.6-bin", "8.7-all", "8.7-bin", "8.10-bin"}},
+		{"version", CachePruneOptions{Version: "8.7"}, []string{"8.7-all", "8.7-bin"}},
+		{"version with patch", CachePruneOptions{Version: "8.7.0"}, []string{"8.7-all", "8.7-bin"}},
+		{"version is not a prefix", CachePruneOptions{Version: "8.1"}, nil},
+		{"edition", CachePruneOptions{Edition: "all"}, []string{"8.7-all"}},
+		{"no match", CachePruneOptions{Version: "9.0"}, nil},
+		{"older than", CachePruneOptions{OlderThan: 30 * day}, []string{"8.5-bin", "8.7-all"}},
+		{"older than protected", CachePruneOptions{OlderThan: 30 * day, Protected: []string{"8.7-all"}}, []string{"8.5-bin"}},
+		{"keep latest per edition", CachePruneOptions{KeepLatest: 2}, []string{"8.5-bin", "8.6-bin"}},
+		{"keep latest bin", CachePruneOptions{KeepLatest: 1, Edition: "bin"}, []string{"8.5-bin", "8.6-bin", "8.7-bin"}},
+		{"max size", CachePruneOptions{MaxSize: 300}, []string{"8.5-bin", "8.7-all"}},
+		{"max size protected", CachePruneOptions{MaxSize: 300, Protected: []string{"8.7-all"}}, []string{"8.5-bin", "8.6-bin", "8.7-bin"}},
+		{"max size counts unmatched entries", CachePruneOptions{MaxSize: 400, Edition: "bin"}, []string{"8.5-bin", "8.6-bin"}},
+		{"older than within max size", CachePruneOptions{OlderThan: 30 * day, MaxSize: 300}, []string{"8.5-bin", "8.7-all"}},
+		{"max size not reached", CachePruneOptions{MaxSize: 1 << 20}, nil},
+	}
+	for _, tt := range tests {
+		candidates, err := PlanCachePrune(tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, candidate := range candidates {
+			if candidate.Reason == "" {
+				t.Errorf("%s: %s has no reason", tt.name, candidate.File)
+			}
+			got = append(got, candidate.Version+"-"+candidate.Edition)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: PlanCachePrune = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, version := range []string{"8.7.x", "v8.7", "latest"} {
+		if _, err := PlanCachePrune(CachePruneOptions{Version: version}); err == nil {
+			t.Errorf("PlanCachePrune(Version: %q): want error", version)
+		}
+	}
+}
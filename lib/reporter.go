@@ -0,0 +1,312 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/uniseg"
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/term"
+)
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventStarted     EventType = "started"      // 开始下载、复制或解压
+	EventProgress    EventType = "progress"     // 传输进度
+	EventMirrorTried EventType = "mirror_tried" // 已检查镜像源可用性
+	EventFileDeleted EventType = "file_deleted" // 已删除文件
+	EventDone        EventType = "done"         // 下载、复制或解压结束（失败时Error非空）
+	EventMessage     EventType = "message"      // 其他说明信息
+)
+
+// 任务类型
+const (
+	TaskDownload = "download" // 从镜像下载
+	TaskCopy     = "copy"     // 从缓存复制到目标目录
+	TaskUnpack   = "unpack"   // 解压发行包
+)
+
+// Event 库函数执行过程中产生的事件
+type Event struct {
+	Type    EventType `json:"type"`
+	Task    string    `json:"task,omitempty"`    // 任务类型 (download/copy/unpack)
+	Mirror  string    `json:"mirror,omitempty"`  // 镜像源名称
+	URL     string    `json:"url,omitempty"`     // 下载地址
+	Path    string    `json:"path,omitempty"`    // 相关文件路径
+	Current int64     `json:"current,omitempty"` // 已传输字节数
+	Total   int64     `json:"total,omitempty"`   // 总字节数，未知时为-1
+	Error   string    `json:"error,omitempty"`   // 失败原因
+	Message string    `json:"message,omitempty"` // 面向用户的说明（已翻译）
+}
+
+// Reporter 接收库函数产生的事件，由调用方决定如何展示
+// 实现需要支持并发调用
+type Reporter interface {
+	Report(event Event)
+}
+
+// 向reporter发送事件，reporter为nil时忽略
+func report(reporter Reporter, event Event) {
+	if reporter != nil {
+		reporter.Report(event)
+	}
+}
+
+// 发送说明信息事件
+func reportMessage(reporter Reporter, message string) {
+	report(reporter, Event{Type: EventMessage, Message: message})
+}
+
+// 为说明信息的每一行加上前缀，用于区分并发处理的各个Gradle版本
+type prefixReporter struct {
+	reporter Reporter
+	prefix   string
+}
+
+func (r prefixReporter) Report(event Event) {
+	if event.Message != "" {
+		lines := strings.Split(event.Message, "\n")
+		for i, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				lines[i] = r.prefix + line
+			}
+		}
+		event.Message = strings.Join(lines, "\n")
+	}
+	report(r.reporter, event)
+}
+
+// 将写入的字节数转换为进度事件，用于io.Copy
+type progressWriter struct {
+	reporter Reporter
+	event    Event
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.event.Current += int64(len(p))
+	report(w.reporter, w.event)
+	return len(p), nil
+}
+
+// 创建从current字节开始计数的进度写入器
+func newProgressWriter(reporter Reporter, task, path string, current, total int64) *progressWriter {
+	return &progressWriter{
+		reporter: reporter,
+		event:    Event{Type: EventProgress, Task: task, Path: path, Current: current, Total: total},
+	}
+}
+
+// SilentReporter 忽略所有事件
+type SilentReporter struct{}
+
+func (SilentReporter) Report(Event) {}
+
+// 进度条的最小重绘间隔
+const terminalRedrawInterval = 50 * time.Millisecond
+
+// TerminalReporter 在终端中输出说明信息，并以进度条显示下载和复制进度
+// 同时有多个进度条时（如并发处理多个Gradle版本），在标准错误中逐行显示所有进度条
+// 标准错误不是终端时（如重定向到日志文件）不显示动态进度条，只在传输结束时输出一行最终进度
+type TerminalReporter struct {
+	out   io.Writer // 说明信息的输出位置，进度条始终输出到标准错误
+	plain bool      // 标准错误不是终端，不使用\r和光标控制序列
+	mu    sync.Mutex
+	bars  map[string]*progressbar.ProgressBar
+	keys  []string // 进度条的显示顺序
+
+	drawn    int       // 当前显示的进度条行数
+	inline   bool      // 只有一个进度条时不换行，光标停留在进度条所在行
+	width    int       // 单行进度条的显示宽度，用于清除
+	lastDraw time.Time // 上次重绘时间
+}
+
+// NewTerminalReporter 创建终端输出，说明信息写入out
+func NewTerminalReporter(out io.Writer) *TerminalReporter {
+	return &TerminalReporter{
+		out:   out,
+		plain: !term.IsTerminal(int(os.Stderr.Fd())),
+		bars:  make(map[string]*progressbar.ProgressBar),
+	}
+}
+
+func (r *TerminalReporter) Report(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := event.Task + "\x00" + event.Path
+	switch event.Type {
+	case EventStarted:
+		if event.Task == TaskDownload || event.Task == TaskCopy {
+			bar := newTerminalBar(event.Task, event.Path, event.Total)
+			bar.Set64(event.Current)
+			if _, ok := r.bars[key]; !ok {
+				r.keys = append(r.keys, key)
+			}
+			r.bars[key] = bar
+			r.redraw()
+		}
+	case EventProgress:
+		if bar, ok := r.bars[key]; ok {
+			bar.Set64(event.Current)
+			if time.Since(r.lastDraw) >= terminalRedrawInterval || event.Current == event.Total {
+				r.redraw()
+			}
+		}
+		return
+	case EventDone:
+		if bar, ok := r.bars[key]; ok {
+			r.clear()
+			r.removeBar(key)
+			line := strings.TrimPrefix(bar.String(), "\r")
+			if event.Error != "" {
+				// 保留失败时的进度
+				if line != "" {
+					fmt.Fprintln(os.Stderr, line)
+				}
+			} else {
+				bar.Finish()
+				fmt.Fprint(os.Stderr, strings.TrimPrefix(bar.String(), "\r")+terminalBarCompletion(event.Task))
+			}
+			r.draw()
+		}
+	}
+
+	if event.Message != "" {
+		r.clear()
+		fmt.Fprint(r.out, event.Message)
+		if !strings.HasSuffix(event.Message, "\n") {
+			fmt.Fprintln(r.out)
+		}
+		r.draw()
+	}
+}
+
+// 删除进度条
+func (r *TerminalReporter) removeBar(key string) {
+	delete(r.bars, key)
+	for i, k := range r.keys {
+		if k == key {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// 清除已显示的进度条，需持有mu
+func (r *TerminalReporter) clear() {
+	switch {
+	case r.drawn == 0:
+	case r.inline:
+		fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", r.width))
+	default:
+		// 光标上移到第一个进度条所在行，并清除到屏幕末尾
+		fmt.Fprintf(os.Stderr, "\033[%dA\033[J", r.drawn)
+	}
+	r.drawn = 0
+}
+
+// 显示所有进度条，需持有mu
+func (r *TerminalReporter) draw() {
+	r.lastDraw = time.Now()
+	if r.plain {
+		return
+	}
+	if len(r.keys) == 1 {
+		line := strings.TrimPrefix(r.bars[r.keys[0]].String(), "\r")
+		fmt.Fprint(os.Stderr, "\r"+line)
+		r.drawn, r.inline, r.width = 1, true, uniseg.StringWidth(line)
+		return
+	}
+	for _, key := range r.keys {
+		fmt.Fprintln(os.Stderr, strings.TrimPrefix(r.bars[key].String(), "\r"))
+	}
+	r.drawn, r.inline = len(r.keys), false
+}
+
+// 重新显示所有进度条，需持有mu
+func (r *TerminalReporter) redraw() {
+	r.clear()
+	r.draw()
+}
+
+// 创建下载或复制进度条，进度条只记录状态，由TerminalReporter统一显示
+func newTerminalBar(task, path string, total int64) *progressbar.ProgressBar {
+	description := T("📥 下载进度")
+	if task == TaskCopy {
+		description = T("📋 复制进度")
+	}
+	// 并发时用文件名区分各个进度条
+	name := strings.TrimSuffix(filepath.Base(path), ".partial")
+
+	return progressbar.NewOptions64(
+		total,
+		progressbar.OptionSetDescription(description+" "+name),
+		progressbar.OptionSetWriter(io.Discard),
+		progressbar.OptionShowBytes(true),
+		progressbar.OptionSetWidth(30),
+		progressbar.OptionThrottle(terminalRedrawInterval),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSpinnerType(9),
+		progressbar.OptionSetTheme(progressbar.Theme{
+			Saucer:        "█",
+			SaucerHead:    "🟢",
+			SaucerPadding: "░",
+			BarStart:      "|",
+			BarEnd:        "|",
+		}),
+	)
+}
+
+// 进度条完成后显示的说明
+func terminalBarCompletion(task string) string {
+	if task == TaskCopy {
+		return T("✅ 复制完成\n")
+	}
+	return T("✅ 下载完成\n")
+}
+
+// 进度事件的最小输出间隔，避免JSON Lines输出过多
+const jsonProgressInterval = 250 * time.Millisecond
+
+// JSONLinesReporter 将每个事件以一行JSON的形式输出，便于其他程序解析
+type JSONLinesReporter struct {
+	mu           sync.Mutex
+	encoder      *json.Encoder
+	lastProgress map[string]time.Time
+}
+
+// NewJSONLinesReporter 创建JSON Lines输出，事件写入out
+func NewJSONLinesReporter(out io.Writer) *JSONLinesReporter {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	return &JSONLinesReporter{encoder: encoder, lastProgress: make(map[string]time.Time)}
+}
+
+func (r *JSONLinesReporter) Report(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := event.Task + "\x00" + event.Path
+	switch event.Type {
+	case EventProgress:
+		// 限制进度事件频率，传输完成时总是输出
+		now := time.Now()
+		if now.Sub(r.lastProgress[key]) < jsonProgressInterval && event.Current != event.Total {
+			return
+		}
+		r.lastProgress[key] = now
+	case EventDone:
+		delete(r.lastProgress, key)
+	}
+
+	event.Message = strings.TrimSpace(event.Message)
+	r.encoder.Encode(event)
+}
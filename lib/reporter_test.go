@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 标准错误重定向到文件时只输出完整的行，不使用\r和光标控制序列
+func TestTerminalReporterPlainOutput(t *testing.T) {
+	stderr, err := os.Create(filepath.Join(t.TempDir(), "stderr.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+	saved := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = saved }()
+
+	var out bytes.Buffer
+	reporter := NewTerminalReporter(&out)
+	for _, path := range []string{"gradle-8.6-bin.zip.partial", "gradle-8.7-bin.zip.partial"} {
+		report(reporter, Event{Type: EventStarted, Task: TaskDownload, Path: path, Total: 1000})
+	}
+	for current := int64(100); current <= 1000; current += 100 {
+		report(reporter, Event{Type: EventProgress, Task: TaskDownload, Path: "gradle-8.6-bin.zip.partial", Current: current, Total: 1000})
+		report(reporter, Event{Type: EventProgress, Task: TaskDownload, Path: "gradle-8.7-bin.zip.partial", Current: current / 2, Total: 1000})
+	}
+	reportMessage(reporter, "checking")
+	report(reporter, Event{Type: EventDone, Task: TaskDownload, Path: "gradle-8.6-bin.zip.partial", Current: 1000, Total: 1000})
+	report(reporter, Event{Type: EventDone, Task: TaskDownload, Path: "gradle-8.7-bin.zip.partial", Error: "connection reset"})
+
+	data, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(string(data), "\r\033") {
+		t.Errorf("stderr contains terminal control characters: %q", data)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "gradle-8.6-bin.zip") || !strings.Contains(lines[1], "gradle-8.7-bin.zip") {
+		t.Errorf("stderr = %q, want one final line per download", data)
+	}
+	if out.String() != "checking\n" {
+		t.Errorf("messages = %q, want %q", out.String(), "checking\n")
+	}
+}
@@ -0,0 +1,137 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// 下载重试策略
+const (
+	maxMirrorAttempts = 4  // 每个镜像连续无进展的最大尝试次数
+	maxTotalAttempts  = 20 // 每个镜像的总尝试次数上限，即使每次都有进展
+)
+
+// 重试前的退避等待时间（测试中会缩短）
+var (
+	retryBaseDelay = 2 * time.Second  // 首次重试前的等待时间
+	retryMaxDelay  = 30 * time.Second // 重试等待时间上限
+)
+
+// 单个镜像的失败原因
+type mirrorFailure struct {
+	mirror string
+	reason string
+}
+
+// 所有镜像都失败时返回的错误，包含每个镜像的失败原因
+type downloadFailedError struct {
+	edition  string
+	failures []mirrorFailure
+}
+
+func (e *downloadFailedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, T("所有%s版镜像源都不可用或下载失败:"), e.edition)
+	for _, failure := range e.failures {
+		fmt.Fprintf(&b, "\n  - %s: %s", failure.mirror, failure.reason)
+	}
+	return b.String()
+}
+
+// 判断错误是否为临时错误（值得在同一镜像上重试）
+// 只重试网络错误以及服务端错误和限流，磁盘已满、没有权限等本地文件错误重试也无法解决
+func isTransientError(err error) bool {
+	var sizeErr *sizeMismatchError
+	if errors.As(err, &sizeErr) {
+		return false
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		// 服务端错误和限流可以重试，其他状态码（如404）换镜像也许能解决，但重试无意义
+		return statusErr.code >= 500 ||
+			statusErr.code == http.StatusRequestTimeout ||
+			statusErr.code == http.StatusTooManyRequests
+	}
+
+	// 网络错误（连接失败、超时等）先于本地文件错误判断，其内部也可能包含*os.SyscallError
+	// 不能用net.Error判断：syscall.Errno也实现了该接口
+	var urlErr *url.Error
+	var opErr *net.OpError
+	if errors.As(err, &urlErr) || errors.As(err, &opErr) {
+		return true
+	}
+	var pathErr *os.PathError
+	var linkErr *os.LinkError
+	var syscallErr *os.SyscallError
+	if errors.As(err, &pathErr) || errors.As(err, &linkErr) || errors.As(err, &syscallErr) {
+		return false
+	}
+
+	// 其余错误来自读取响应（连接提前关闭、已下载部分与服务器不一致等），视为临时错误
+	return true
+}
+
+// 计算第attempt次重试前的退避等待时间（指数增长，有上限）
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// 在单个镜像上下载文件，临时错误时退避重试并断点续传
+// 已下载部分比之前更多时重新计数，避免大文件在慢速链路上被过早放弃；
+// 总尝试次数不超过maxTotalAttempts，避免反复下载却始终无法完成时无限重试
+func downloadWithRetry(ctx context.Context, mirror Mirror, url, destPath string, probe *probeResult, reporter Reporter) error {
+	client := newHTTPClient(mirror, downloadAttemptTimeout)
+	attempt, total := 0, 0
+	var downloaded int64 // 目前为止.partial文件的最大长度
+	for {
+		attempt++
+		total++
+		reportMessage(reporter, T("[%s] 第 %d/%d 次尝试\n", mirror.Name, attempt, maxMirrorAttempts))
+
+		written, err := downloadFile(ctx, client, url, destPath, probe, reporter)
+		if err == nil {
+			return nil
+		}
+		// 被取消时不再重试
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		reportMessage(reporter, T("[%s] 第 %d 次尝试失败: %v\n", mirror.Name, attempt, err))
+
+		if !isTransientError(err) {
+			return err
+		}
+		// 数据被丢弃（如续传失败后重新开始）时.partial文件不会变长，不算作进展
+		if info, statErr := os.Stat(partialFilePath(destPath)); written > 0 && statErr == nil && info.Size() > downloaded {
+			downloaded = info.Size()
+			attempt = 0
+		}
+		if attempt >= maxMirrorAttempts || total >= maxTotalAttempts {
+			return fmt.Errorf(T("重试%d次后仍失败: %v"), total, err)
+		}
+
+		delay := retryDelay(max(attempt, 1))
+		if info, statErr := os.Stat(partialFilePath(destPath)); statErr == nil {
+			reportMessage(reporter, T("[%s] %v 后从 %d 字节处继续下载...\n", mirror.Name, delay, info.Size()))
+		} else {
+			reportMessage(reporter, T("[%s] %v 后重试...\n", mirror.Name, delay))
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
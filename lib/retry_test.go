@@ -0,0 +1,291 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsTransientError(t *testing.T) {
+	connRefused := &url.Error{Op: "Get", URL: "https://example.com/gradle-8.7-bin.zip", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+	}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"500", &httpStatusError{code: 500}, true},
+		{"503", &httpStatusError{code: 503}, true},
+		{"408", &httpStatusError{code: 408}, true},
+		{"429", &httpStatusError{code: 429}, true},
+		{"403", &httpStatusError{code: 403}, false},
+		{"404", &httpStatusError{code: 404}, false},
+		{"size mismatch", &sizeMismatchError{expected: 100, actual: 99}, false},
+		{"connection refused", fmt.Errorf(T("下载失败: %w"), connRefused), true},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"partial mismatch", errors.New(T("已下载部分与服务器文件不一致，重新下载")), true},
+		{"permission denied", fmt.Errorf(T("创建文件失败: %w"), &os.PathError{Op: "open", Path: "/cache/a.partial", Err: syscall.EACCES}), false},
+		{"disk full", &os.PathError{Op: "write", Path: "/cache/a.partial", Err: syscall.ENOSPC}, false},
+		{"rename failed", fmt.Errorf(T("重命名下载文件失败: %w"), &os.LinkError{Op: "rename", Old: "a.partial", New: "a", Err: syscall.EXDEV}), false},
+		{"syscall", os.NewSyscallError("fsync", syscall.EIO), false},
+	}
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.want {
+			t.Errorf("%s: isTransientError(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+// 缩短重试等待时间，测试结束后恢复
+func fastRetries(t *testing.T) {
+	t.Helper()
+	base, limit := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = base, limit })
+}
+
+// 使用指定的镜像源，测试结束后恢复
+func useTestMirrors(t *testing.T, list ...Mirror) {
+	t.Helper()
+	saved, overridden := mirrors, mirrorsOverridden
+	mirrors, mirrorsOverridden = list, true
+	t.Cleanup(func() { mirrors, mirrorsOverridden = saved, overridden })
+}
+
+// 从请求的Range位置开始只发送chunk字节后中断连接，模拟每次都有进展但始终无法完成的下载
+func dropAfterChunk(chunk int) func(w http.ResponseWriter, r *http.Request, n int) bool {
+	return func(w http.ResponseWriter, r *http.Request, n int) bool {
+		offset := 0
+		if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+			offset, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rangeHeader, "bytes="), "-"))
+		}
+		if offset == 0 {
+			dropAfter(w, testDistribution, chunk)
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(testDistribution)-1, len(testDistribution)))
+		w.Header().Set("Content-Length", fmt.Sprint(len(testDistribution)-offset))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(testDistribution[offset : offset+chunk])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func TestDownloadWithRetryResumesAfterDrop(t *testing.T) {
+	fastRetries(t)
+	half := len(testDistribution) / 2
+	files := &testFileServer{content: testDistribution, etag: `"v1"`}
+	files.override = func(w http.ResponseWriter, r *http.Request, n int) bool {
+		if n == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			dropAfter(w, testDistribution, half)
+		}
+		return false
+	}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+
+	if err := downloadWithRetry(context.Background(), Mirror{Name: "test"}, server.URL, destPath, testProbe(t, server.URL), nil); err != nil {
+		t.Fatal(err)
+	}
+	if files.count() != 2 {
+		t.Errorf("%d GET requests, want 2", files.count())
+	}
+	if got, want := files.request(2).Header.Get("Range"), fmt.Sprintf("bytes=%d-", half); got != want {
+		t.Errorf("retry Range = %q, want %q", got, want)
+	}
+	checkDownloaded(t, destPath, testDistribution)
+}
+
+func TestDownloadWithRetryAttempts(t *testing.T) {
+	fastRetries(t)
+	status := func(code int) func(w http.ResponseWriter, r *http.Request, n int) bool {
+		return func(w http.ResponseWriter, r *http.Request, n int) bool {
+			http.Error(w, http.StatusText(code), code)
+			return true
+		}
+	}
+
+	tests := []struct {
+		name     string
+		override func(w http.ResponseWriter, r *http.Request, n int) bool
+		want     int // 预期的GET请求数
+	}{
+		{"404 is not retried", status(http.StatusNotFound), 1},
+		{"403 is not retried", status(http.StatusForbidden), 1},
+		{"503 until attempts run out", status(http.StatusServiceUnavailable), maxMirrorAttempts},
+		{"429 until attempts run out", status(http.StatusTooManyRequests), maxMirrorAttempts},
+		{"dropped without progress", func(w http.ResponseWriter, r *http.Request, n int) bool {
+			dropAfter(w, testDistribution, 0)
+			return true
+		}, maxMirrorAttempts},
+		{"progress without completing", dropAfterChunk(100), maxTotalAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := &testFileServer{content: testDistribution, override: tt.override}
+			server := newTestFileServer(t, files)
+			destPath := filepath.Join(t.TempDir(), "gradle.zip")
+			probe := &probeResult{ContentLength: int64(len(testDistribution)), AcceptRanges: true}
+
+			err := downloadWithRetry(context.Background(), Mirror{Name: "test"}, server.URL, destPath, probe, nil)
+			if err == nil {
+				t.Fatal("want error")
+			}
+			if files.count() != tt.want {
+				t.Errorf("%d GET requests, want %d (err: %v)", files.count(), tt.want, err)
+			}
+			if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+				t.Errorf("%s exists after a failed download", destPath)
+			}
+		})
+	}
+}
+
+func TestDownloadWithRetryCanceled(t *testing.T) {
+	fastRetries(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	files := &testFileServer{content: testDistribution}
+	files.override = func(w http.ResponseWriter, r *http.Request, n int) bool {
+		cancel()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return true
+	}
+	server := newTestFileServer(t, files)
+	destPath := filepath.Join(t.TempDir(), "gradle.zip")
+	probe := &probeResult{ContentLength: int64(len(testDistribution)), AcceptRanges: true}
+
+	err := downloadWithRetry(ctx, Mirror{Name: "test"}, server.URL, destPath, probe, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if files.count() != 1 {
+		t.Errorf("%d GET requests, want 1", files.count())
+	}
+}
+
+// 第一个镜像失败时换下一个镜像下载，已下载的部分在下一个镜像上续传
+func TestDownloadGradleFailover(t *testing.T) {
+	fastRetries(t)
+	sum := sha256.Sum256(testDistribution)
+	expectedSum := hex.EncodeToString(sum[:])
+	half := len(testDistribution) / 2
+
+	tests := []struct {
+		name       string
+		first      func(w http.ResponseWriter, r *http.Request, n int) bool
+		firstGETs  int
+		wantResume bool // 第二个镜像是否续传第一个镜像已下载的部分
+	}{
+		{"5xx", func(w http.ResponseWriter, r *http.Request, n int) bool {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return true
+		}, maxMirrorAttempts, false},
+		{"404", func(w http.ResponseWriter, r *http.Request, n int) bool {
+			http.NotFound(w, r)
+			return true
+		}, 1, false},
+		{"dropped mid-body", func(w http.ResponseWriter, r *http.Request, n int) bool {
+			dropAfter(w, testDistribution, half)
+			return true
+		}, 1 + maxMirrorAttempts, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+
+			first := &testFileServer{content: testDistribution, etag: `"a"`, override: tt.first}
+			second := &testFileServer{content: testDistribution, etag: `"b"`}
+			useTestMirrors(t,
+				Mirror{Name: "first", URL: newTestFileServer(t, first).URL + "/gradle-{{version}}-bin.zip"},
+				Mirror{Name: "second", URL: newTestFileServer(t, second).URL + "/gradle-{{version}}-bin.zip"},
+			)
+
+			path, err := DownloadGradle(context.Background(), "8.7", "bin", expectedSum, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != CachedGradlePath("8.7", "bin") {
+				t.Errorf("path = %s, want %s", path, CachedGradlePath("8.7", "bin"))
+			}
+			if first.count() != tt.firstGETs {
+				t.Errorf("first mirror got %d GET requests, want %d", first.count(), tt.firstGETs)
+			}
+			if second.count() != 1 {
+				t.Fatalf("second mirror got %d GET requests, want 1", second.count())
+			}
+			if got := second.request(1).Header.Get("Range") != ""; got != tt.wantResume {
+				t.Errorf("second mirror resumed = %v, want %v", got, tt.wantResume)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, testDistribution) {
+				t.Error("cached file content mismatch")
+			}
+			entries, err := ListCacheEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Mirror != "second" || entries[0].SHA256 != expectedSum {
+				t.Errorf("cache entries = %+v, want one entry from second", entries)
+			}
+		})
+	}
+}
+
+func TestDownloadGradleAllMirrorsFail(t *testing.T) {
+	fastRetries(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	unavailable := &testFileServer{content: testDistribution, override: func(w http.ResponseWriter, r *http.Request, n int) bool {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return true
+	}}
+	// 探测时即返回404
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	useTestMirrors(t,
+		Mirror{Name: "unavailable", URL: newTestFileServer(t, unavailable).URL + "/gradle-{{version}}-bin.zip"},
+		Mirror{Name: "missing", URL: missing.URL + "/gradle-{{version}}-bin.zip"},
+	)
+
+	_, err := DownloadGradle(context.Background(), "8.7", "bin", "", nil)
+	var failed *downloadFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("err = %v, want downloadFailedError", err)
+	}
+	if len(failed.failures) != 2 || failed.failures[0].mirror != "unavailable" || failed.failures[1].mirror != "missing" {
+		t.Errorf("failures = %+v", failed.failures)
+	}
+	if unavailable.count() != maxMirrorAttempts {
+		t.Errorf("unavailable mirror got %d GET requests, want %d", unavailable.count(), maxMirrorAttempts)
+	}
+	if _, err := os.Stat(CachedGradlePath("8.7", "bin")); !os.IsNotExist(err) {
+		t.Error("cache file created although every mirror failed")
+	}
+}
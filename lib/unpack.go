@@ -0,0 +1,146 @@
+package lib
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 获取Gradle Wrapper解压完成标记文件路径（与Wrapper的命名规则一致）
+func wrapperMarkerPath(zipPath string) string {
+	return zipPath + ".ok"
+}
+
+// 获取Gradle Wrapper锁文件路径
+func wrapperLockPath(zipPath string) string {
+	return zipPath + ".lck"
+}
+
+// UnpackGradleDistribution 按Gradle Wrapper的方式安装发行包
+// targetDir为 dists/gradle-X-bin/<hash> 目录，发行包需已复制到该目录中。
+// 解压得到 <hash>/gradle-X，然后写入 .ok 标记文件并删除 .lck 锁文件，
+// 之后Wrapper无需联网即可直接使用该发行包；ctx取消时停止解压并清理临时目录
+func UnpackGradleDistribution(ctx context.Context, version, edition, targetDir string, reporter Reporter) error {
+	zipPath := filepath.Join(targetDir, fmt.Sprintf("gradle-%s-%s.zip", version, edition))
+	if _, err := os.Stat(zipPath); err != nil {
+		return fmt.Errorf(T("发行包不存在: %v"), err)
+	}
+
+	// 先解压到临时目录，完成后再重命名，避免留下解压一半的目录
+	tempDir, err := os.MkdirTemp(targetDir, ".unpack-")
+	if err != nil {
+		return fmt.Errorf(T("创建临时目录失败: %v"), err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	report(reporter, Event{Type: EventStarted, Task: TaskUnpack, Path: zipPath, Message: T("正在解压 %s ...\n", filepath.Base(zipPath))})
+	if err := unzipFile(ctx, zipPath, tempDir); err != nil {
+		report(reporter, Event{Type: EventDone, Task: TaskUnpack, Path: zipPath, Error: err.Error()})
+		return fmt.Errorf(T("解压失败: %v"), err)
+	}
+
+	// 发行包内应只有一个 gradle-X 根目录
+	rootName := "gradle-" + version
+	if _, err := os.Stat(filepath.Join(tempDir, rootName, "lib")); err != nil {
+		return fmt.Errorf(T("发行包结构无效，缺少 %s/lib 目录"), rootName)
+	}
+
+	// Wrapper要求哈希目录下只有一个子目录，清理之前解压失败留下的目录
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		return fmt.Errorf(T("读取目标目录失败: %v"), err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != filepath.Base(tempDir) {
+			if err := os.RemoveAll(filepath.Join(targetDir, entry.Name())); err != nil {
+				return fmt.Errorf(T("清理旧目录失败: %v"), err)
+			}
+		}
+	}
+
+	if err := os.Rename(filepath.Join(tempDir, rootName), filepath.Join(targetDir, rootName)); err != nil {
+		return fmt.Errorf(T("移动解压目录失败: %v"), err)
+	}
+
+	// 写入完成标记并删除锁文件
+	if err := writeFileAtomic(wrapperMarkerPath(zipPath), nil, 0644); err != nil {
+		return fmt.Errorf(T("写入.ok标记文件失败: %v"), err)
+	}
+	if err := os.Remove(wrapperLockPath(zipPath)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf(T("删除.lck文件失败: %v"), err)
+	}
+
+	report(reporter, Event{Type: EventDone, Task: TaskUnpack, Path: zipPath,
+		Message: T("✅ 已解压到: %s\n", filepath.Join(filepath.Base(targetDir), rootName))})
+	return nil
+}
+
+// 解压ZIP文件到指定目录，保留文件权限（bin/gradle需要可执行权限）
+func unzipFile(ctx context.Context, zipPath, destDir string) error {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	destDir = filepath.Clean(destDir)
+	for _, file := range reader.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		path := filepath.Join(destDir, file.Name)
+
+		// 防止路径穿越（zip slip）
+		if !strings.HasPrefix(path, destDir+string(os.PathSeparator)) {
+			return fmt.Errorf(T("非法的文件路径: %s"), file.Name)
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := extractZipEntry(ctx, file, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 解压单个文件
+func extractZipEntry(ctx context.Context, file *zip.File, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	mode := file.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+	// 与Wrapper一样确保启动脚本可执行
+	if runtime.GOOS != "windows" && filepath.Base(filepath.Dir(path)) == "bin" {
+		mode |= 0755
+	}
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, &contextReader{ctx: ctx, r: src})
+	return err
+}
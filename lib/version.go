@@ -0,0 +1,115 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Gradle版本号的预发布阶段，按发布先后排序
+const (
+	StageSnapshot  = "snapshot"  // 每日构建，例如 8.10-20240101000000+0000
+	StageMilestone = "milestone" // 里程碑版，例如 8.10-milestone-3
+	StagePreview   = "preview"   // 预览版，例如 4.0-preview-1（早期版本使用）
+	StageRC        = "rc"        // 候选版，例如 9.0-rc-1
+	StageFinal     = ""          // 正式版
+)
+
+// 各阶段的先后顺序
+var stageOrder = map[string]int{
+	StageSnapshot:  0,
+	StageMilestone: 1,
+	StagePreview:   2,
+	StageRC:        3,
+	StageFinal:     4,
+}
+
+// 匹配Gradle版本号：主版本.次版本[.修订号][-rc-N|-milestone-N|-preview-N|-时间戳]
+var gradleVersionPattern = regexp.MustCompile(
+	`^(\d+)\.(\d+)(?:\.(\d+))?(?:-(?:(rc|milestone|preview)-(\d+)|(\d{14}[+-]\d{4})))?$`)
+
+// 匹配发行包文件名：gradle-版本号-版本类型.zip
+var gradleZipPattern = regexp.MustCompile(`^gradle-(.+)-(bin|all)\.zip$`)
+
+// GradleVersion 可比较的Gradle版本号
+type GradleVersion struct {
+	Major       int
+	Minor       int
+	Patch       int
+	Stage       string // 预发布阶段，正式版为空
+	StageNumber int    // rc/milestone/preview的序号
+	Timestamp   string // 每日构建的时间戳
+	original    string
+}
+
+// ParseGradleVersion 解析Gradle版本号
+// 支持 8.7、8.14.2、9.0-rc-1、8.10-milestone-3 以及每日构建版本等格式
+func ParseGradleVersion(version string) (GradleVersion, error) {
+	matches := gradleVersionPattern.FindStringSubmatch(version)
+	if matches == nil {
+		return GradleVersion{}, fmt.Errorf(T("无效的Gradle版本号: %s"), version)
+	}
+
+	v := GradleVersion{original: version}
+	v.Major, _ = strconv.Atoi(matches[1])
+	v.Minor, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		v.Patch, _ = strconv.Atoi(matches[3])
+	}
+
+	switch {
+	case matches[4] != "":
+		v.Stage = matches[4]
+		v.StageNumber, _ = strconv.Atoi(matches[5])
+	case matches[6] != "":
+		v.Stage = StageSnapshot
+		v.Timestamp = matches[6]
+	}
+	return v, nil
+}
+
+// String 返回原始版本号字符串（与发行包文件名中的一致）
+func (v GradleVersion) String() string {
+	return v.original
+}
+
+// IsPrerelease 判断是否为预发布版本
+func (v GradleVersion) IsPrerelease() bool {
+	return v.Stage != StageFinal
+}
+
+// Compare 比较两个版本号，v较旧时返回-1，相同返回0，较新返回1
+func (v GradleVersion) Compare(other GradleVersion) int {
+	pairs := [][2]int{
+		{v.Major, other.Major},
+		{v.Minor, other.Minor},
+		{v.Patch, other.Patch},
+		{stageOrder[v.Stage], stageOrder[other.Stage]},
+		{v.StageNumber, other.StageNumber},
+	}
+	for _, pair := range pairs {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+
+	// 每日构建按时间戳比较
+	switch {
+	case v.Timestamp < other.Timestamp:
+		return -1
+	case v.Timestamp > other.Timestamp:
+		return 1
+	}
+	return 0
+}
+
+// ValidateEdition 检查版本类型是否有效
+func ValidateEdition(edition string) error {
+	if edition != "bin" && edition != "all" {
+		return fmt.Errorf(T("edition参数必须为 'bin' 或 'all'，当前为: %s"), edition)
+	}
+	return nil
+}
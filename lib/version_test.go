@@ -0,0 +1,107 @@
+package lib
+
+import "testing"
+
+func TestParseGradleVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    GradleVersion
+		wantErr bool
+	}{
+		{version: "8.7", want: GradleVersion{Major: 8, Minor: 7}},
+		{version: "8.14.2", want: GradleVersion{Major: 8, Minor: 14, Patch: 2}},
+		{version: "9.0-rc-1", want: GradleVersion{Major: 9, Stage: StageRC, StageNumber: 1}},
+		{version: "8.10-milestone-3", want: GradleVersion{Major: 8, Minor: 10, Stage: StageMilestone, StageNumber: 3}},
+		{version: "4.0-preview-1", want: GradleVersion{Major: 4, Stage: StagePreview, StageNumber: 1}},
+		{version: "8.10-20240101000000+0000", want: GradleVersion{Major: 8, Minor: 10, Stage: StageSnapshot, Timestamp: "20240101000000+0000"}},
+		{version: "", wantErr: true},
+		{version: "8", wantErr: true},
+		{version: "8.7.1.2", wantErr: true},
+		{version: "8.7-beta-1", wantErr: true},
+		{version: "v8.7", wantErr: true},
+		{version: "8.7-bin", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGradleVersion(tt.version)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseGradleVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		tt.want.original = tt.version
+		if got != tt.want {
+			t.Errorf("ParseGradleVersion(%q) = %+v, want %+v", tt.version, got, tt.want)
+		}
+		if got.String() != tt.version {
+			t.Errorf("ParseGradleVersion(%q).String() = %q", tt.version, got.String())
+		}
+		if got.IsPrerelease() != (tt.want.Stage != StageFinal) {
+			t.Errorf("ParseGradleVersion(%q).IsPrerelease() = %v", tt.version, got.IsPrerelease())
+		}
+	}
+}
+
+func TestGradleVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"8.7", "8.7", 0},
+		{"8.7", "8.7.0", 0},
+		{"8.7", "8.8", -1},
+		{"8.10", "8.9", 1},
+		{"8.14.2", "8.14.10", -1},
+		{"7.6.4", "8.0", -1},
+		{"9.0-rc-1", "9.0", -1},
+		{"9.0-rc-2", "9.0-rc-1", 1},
+		{"8.10-milestone-3", "8.10-rc-1", -1},
+		{"4.0-preview-1", "4.0-rc-1", -1},
+		{"4.0-milestone-2", "4.0-preview-1", -1},
+		{"8.10-20240101000000+0000", "8.10-milestone-1", -1},
+		{"8.10-20240101000000+0000", "8.10-20240102000000+0000", -1},
+		{"8.10-20240101000000+0000", "8.9", 1},
+	}
+	for _, tt := range tests {
+		a, err := ParseGradleVersion(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseGradleVersion(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Compare(a); got != -tt.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestExtractGradleVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		edition string
+		wantErr bool
+	}{
+		{"gradle-8.7-bin.zip", "8.7", "bin", false},
+		{"gradle-9.0-rc-1-all.zip", "9.0-rc-1", "all", false},
+		{"gradle-8.7-src.zip", "", "", true},
+		{"gradle-latest-bin.zip", "", "", true},
+		{"gradle-8.7-bin.zip.partial", "", "", true},
+	}
+	for _, tt := range tests {
+		version, edition, err := extractGradleVersion(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("extractGradleVersion(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if version != tt.version || edition != tt.edition {
+			t.Errorf("extractGradleVersion(%q) = %q, %q, want %q, %q", tt.name, version, edition, tt.version, tt.edition)
+		}
+	}
+}
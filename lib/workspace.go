@@ -0,0 +1,240 @@
+package lib
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Wrapper配置文件在工作区中的相对路径
+const wrapperPropertiesPath = "gradle/wrapper/gradle-wrapper.properties"
+
+// Wrapper默认的发行包存放位置
+const (
+	baseGradleUserHome  = "GRADLE_USER_HOME"
+	baseProject         = "PROJECT"
+	defaultDistsSubPath = "wrapper/dists"
+)
+
+// WrapperProperties gradle-wrapper.properties中与发行包相关的配置
+type WrapperProperties struct {
+	DistributionURL       string // distributionUrl
+	DistributionSha256Sum string // distributionSha256Sum，未设置时为空
+	DistributionBase      string // distributionBase
+	DistributionPath      string // distributionPath
+	ZipStoreBase          string // zipStoreBase
+	ZipStorePath          string // zipStorePath
+}
+
+// WorkspaceStatus MCreator工作区所需Gradle的状态
+type WorkspaceStatus struct {
+	Dir        string             // 工作区目录
+	Properties *WrapperProperties // Wrapper配置
+	Version    string             // 所需Gradle版本号
+	Edition    string             // 所需版本类型 (bin/all)
+	InstallDir string             // Wrapper查找发行包的目录
+	Installed  bool               // 是否已解压安装（存在.ok标记）
+	Cached     bool               // 缓存目录中是否已有可用的发行包
+	Mismatch   bool               // 缓存中的发行包与distributionSha256Sum不一致
+	Err        error              // 读取或解析失败的原因
+}
+
+// ReadWrapperProperties 读取工作区的gradle/wrapper/gradle-wrapper.properties
+func ReadWrapperProperties(workspaceDir string) (*WrapperProperties, error) {
+	file, err := os.Open(filepath.Join(workspaceDir, filepath.FromSlash(wrapperPropertiesPath)))
+	if err != nil {
+		return nil, fmt.Errorf(T("读取Wrapper配置失败: %v"), err)
+	}
+	defer file.Close()
+
+	values, err := parseProperties(file)
+	if err != nil {
+		return nil, fmt.Errorf(T("解析Wrapper配置失败: %v"), err)
+	}
+
+	props := &WrapperProperties{
+		DistributionURL:       values["distributionUrl"],
+		DistributionSha256Sum: strings.ToLower(values["distributionSha256Sum"]),
+		DistributionBase:      values["distributionBase"],
+		DistributionPath:      values["distributionPath"],
+		ZipStoreBase:          values["zipStoreBase"],
+		ZipStorePath:          values["zipStorePath"],
+	}
+	if props.DistributionURL == "" {
+		return nil, errors.New(T("Wrapper配置中缺少distributionUrl"))
+	}
+	return props, nil
+}
+
+// 解析Java properties格式的内容（支持注释、转义字符和续行）
+func parseProperties(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+
+	var logical strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		// 行尾有奇数个反斜杠表示续行
+		trailing := len(line) - len(strings.TrimRight(line, `\`))
+		if trailing%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+
+		key, value := splitProperty(logical.String())
+		values[unescapeProperty(key)] = unescapeProperty(value)
+		logical.Reset()
+	}
+	if logical.Len() > 0 {
+		key, value := splitProperty(logical.String())
+		values[unescapeProperty(key)] = unescapeProperty(value)
+	}
+	return values, scanner.Err()
+}
+
+// 按第一个未转义的 =、: 或空白拆分键值
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			key := line[:i]
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return key, rest
+		}
+	}
+	return line, ""
+}
+
+// 处理properties中的转义字符，例如 https\://
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// 确定Wrapper存放发行包的dists目录
+// distsDir为MCreator的 GRADLE_USER_HOME/wrapper/dists 目录
+func resolveDistsDir(distsDir, workspaceDir string, props *WrapperProperties) string {
+	distPath := props.DistributionPath
+	if distPath == "" {
+		distPath = defaultDistsSubPath
+	}
+
+	if props.DistributionBase == baseProject {
+		return filepath.Join(workspaceDir, filepath.FromSlash(distPath))
+	}
+	if filepath.ToSlash(filepath.Clean(distPath)) == defaultDistsSubPath {
+		return distsDir
+	}
+	// GRADLE_USER_HOME 为 dists 目录的上两级
+	gradleUserHome := filepath.Dir(filepath.Dir(distsDir))
+	return filepath.Join(gradleUserHome, filepath.FromSlash(distPath))
+}
+
+// CheckWorkspace 检查工作区所需的Gradle版本及其安装状态
+func CheckWorkspace(distsDir, workspaceDir string) WorkspaceStatus {
+	status := WorkspaceStatus{Dir: workspaceDir}
+
+	props, err := ReadWrapperProperties(workspaceDir)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Properties = props
+
+	status.Version, status.Edition, err = ParseDistributionURL(props.DistributionURL)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+
+	status.InstallDir, err = WrapperDistributionDir(resolveDistsDir(distsDir, workspaceDir, props), props.DistributionURL)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+
+	zipName := fmt.Sprintf("gradle-%s-%s.zip", status.Version, status.Edition)
+	if _, err := os.Stat(wrapperMarkerPath(filepath.Join(status.InstallDir, zipName))); err == nil {
+		status.Installed = true
+	}
+	if _, err := os.Stat(CachedGradlePath(status.Version, status.Edition)); err == nil {
+		status.Cached = true
+
+		// 工作区指定了校验和时，核对缓存记录的校验和
+		if props.DistributionSha256Sum != "" {
+			sum, err := CachedChecksum(status.Version, status.Edition)
+			if err != nil || sum != props.DistributionSha256Sum {
+				status.Cached = false
+				status.Mismatch = true
+			}
+		}
+	}
+	return status
+}
+
+// FindWorkspaces 列出目录下所有包含Gradle Wrapper配置的工作区（如 MCreatorWorkspaces）
+func FindWorkspaces(root string) []string {
+	var result []string
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return result
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(wrapperPropertiesPath))); err == nil {
+			result = append(result, dir)
+		}
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package lib
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+	}{
+		{
+			name: "gradle wrapper",
+			content: "#Mon Jan 01 00:00:00 CST 2024\n" +
+				"distributionBase=GRADLE_USER_HOME\n" +
+				"distributionPath=wrapper/dists\n" +
+				"distributionUrl=https\\://services.gradle.org/distributions/gradle-8.7-bin.zip\n" +
+				"zipStoreBase=GRADLE_USER_HOME\n" +
+				"zipStorePath=wrapper/dists\n",
+			want: map[string]string{
+				"distributionBase": "GRADLE_USER_HOME",
+				"distributionPath": "wrapper/dists",
+				"distributionUrl":  "https://services.gradle.org/distributions/gradle-8.7-bin.zip",
+				"zipStoreBase":     "GRADLE_USER_HOME",
+				"zipStorePath":     "wrapper/dists",
+			},
+		},
+		{
+			name:    "comments and blank lines",
+			content: "# comment\n! comment\n\n   \n  key = value  \n",
+			want:    map[string]string{"key": "value  "},
+		},
+		{
+			name:    "separators",
+			content: "a=1\nb:2\nc 3\nd\t=\t4\ne :5\nf\n",
+			want:    map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": ""},
+		},
+		{
+			name:    "continuation lines",
+			content: "url=https\\://example.com/\\\n    gradle-8.7-\\\n    bin.zip\nnext=1\n",
+			want:    map[string]string{"url": "https://example.com/gradle-8.7-bin.zip", "next": "1"},
+		},
+		{
+			name:    "comment inside continuation",
+			content: "a=1\\\n#2\n",
+			want:    map[string]string{"a": "1#2"},
+		},
+		{
+			name:    "escaped backslash is not a continuation",
+			content: "path=C\\:\\\\\nnext=1\n",
+			want:    map[string]string{"path": `C:\`, "next": "1"},
+		},
+		{
+			name:    "continuation at end of file",
+			content: "a=1\\",
+			want:    map[string]string{"a": "1"},
+		},
+		{
+			name:    "escaped separator in key",
+			content: "a\\=b=c\n",
+			want:    map[string]string{"a=b": "c"},
+		},
+		{
+			name:    "later value wins",
+			content: "a=1\na=2\n",
+			want:    map[string]string{"a": "2"},
+		},
+		{
+			name:    "CRLF",
+			content: "a=1\r\nb=2\r\n",
+			want:    map[string]string{"a": "1", "b": "2"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseProperties(strings.NewReader(tt.content))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !maps.Equal(got, tt.want) {
+			t.Errorf("%s: parseProperties = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitProperty(t *testing.T) {
+	tests := []struct {
+		line  string
+		key   string
+		value string
+	}{
+		{"key=value", "key", "value"},
+		{"key = value", "key", "value"},
+		{"key:value", "key", "value"},
+		{"key value", "key", "value"},
+		{"key  =  =value", "key", "=value"},
+		{"key", "key", ""},
+		{"key=", "key", ""},
+		{`a\ b=c`, `a\ b`, "c"},
+		{`a\:b:c`, `a\:b`, "c"},
+		{"url=https\\://a", "url", "https\\://a"},
+	}
+	for _, tt := range tests {
+		key, value := splitProperty(tt.line)
+		if key != tt.key || value != tt.value {
+			t.Errorf("splitProperty(%q) = %q, %q, want %q, %q", tt.line, key, value, tt.key, tt.value)
+		}
+	}
+}
+
+func TestUnescapeProperty(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"plain", "plain"},
+		{`https\://example.com`, "https://example.com"},
+		{`a\=b`, "a=b"},
+		{`a\ b`, "a b"},
+		{`\\`, `\`},
+		{`\t\n\r\f`, "\t\n\r\f"},
+		{`\u0041\u4e2d`, "A中"},
+		{`\u00`, "u00"},
+		{`\uZZZZ`, "uZZZZ"},
+		{`\x`, "x"},
+	}
+	for _, tt := range tests {
+		if got := unescapeProperty(tt.s); got != tt.want {
+			t.Errorf("unescapeProperty(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestReadWrapperProperties(t *testing.T) {
+	write := func(content string) string {
+		dir := t.TempDir()
+		path := filepath.Join(dir, filepath.FromSlash(wrapperPropertiesPath))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	dir := write("distributionUrl=https\\://services.gradle.org/distributions/gradle-8.7-bin.zip\n" +
+		"distributionSha256Sum=544C35D6BD849AE8A5ED0BCEA39BA677DC40F49DF7D1835561582DA2009B961D\n")
+	props, err := ReadWrapperProperties(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.DistributionURL != "https://services.gradle.org/distributions/gradle-8.7-bin.zip" {
+		t.Errorf("DistributionURL = %q", props.DistributionURL)
+	}
+	if props.DistributionSha256Sum != "544c35d6bd849ae8a5ed0bcea39ba677dc40f49df7d1835561582da2009b961d" {
+		t.Errorf("DistributionSha256Sum = %q, want lower case", props.DistributionSha256Sum)
+	}
+
+	if _, err := ReadWrapperProperties(write("distributionBase=GRADLE_USER_HOME\n")); err == nil {
+		t.Error("ReadWrapperProperties without distributionUrl: want error")
+	}
+	if _, err := ReadWrapperProperties(t.TempDir()); err == nil {
+		t.Error("ReadWrapperProperties without gradle-wrapper.properties: want error")
+	}
+}
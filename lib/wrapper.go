@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Gradle官方发行包地址，MCreator生成的工作区默认使用该地址作为distributionUrl
+const officialDistributionURL = "https://services.gradle.org/distributions/gradle-%s-%s.zip"
+
+// OfficialDistributionURL 获取Gradle官方发行包的下载地址
+func OfficialDistributionURL(version, edition string) string {
+	return fmt.Sprintf(officialDistributionURL, version, edition)
+}
+
+// 获取distributionUrl对应的发行包名称（去掉.zip后缀），例如 gradle-8.7-bin
+func wrapperDistName(distributionURL string) (string, error) {
+	u, err := url.Parse(distributionURL)
+	if err != nil {
+		return "", fmt.Errorf(T("无效的distributionUrl: %v"), err)
+	}
+	name := path.Base(u.Path)
+	if name == "" || name == "/" || name == "." {
+		return "", fmt.Errorf(T("无效的distributionUrl: %s"), distributionURL)
+	}
+	return strings.TrimSuffix(name, ".zip"), nil
+}
+
+// 计算Gradle Wrapper的目录哈希：distributionUrl的MD5，以36进制表示
+// 与Wrapper中PathAssembler.getHash的实现一致
+func wrapperURLHash(distributionURL string) string {
+	sum := md5.Sum([]byte(distributionURL))
+	return new(big.Int).SetBytes(sum[:]).Text(36)
+}
+
+// WrapperDistributionDir 计算Gradle Wrapper为distributionUrl使用的安装目录
+// 即 distsDir/<发行包名称>/<哈希>，例如 dists/gradle-8.7-bin/bhs2wmbdwecv87pi65oeuq5iu
+func WrapperDistributionDir(distsDir, distributionURL string) (string, error) {
+	distName, err := wrapperDistName(distributionURL)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(distsDir, distName, wrapperURLHash(distributionURL)), nil
+}
+
+// ParseDistributionURL 从distributionUrl中提取Gradle版本号和版本类型
+func ParseDistributionURL(distributionURL string) (version, edition string, err error) {
+	distName, err := wrapperDistName(distributionURL)
+	if err != nil {
+		return "", "", err
+	}
+	return extractGradleVersion(distName + ".zip")
+}
+
+// InstallFromURL 为distributionUrl预先安装Gradle发行包
+// 无需先让MCreator构建失败，直接计算Wrapper将要查找的目录并放入缓存中的发行包
+// expectedSum为distributionSha256Sum，未设置时传空字符串
+func InstallFromURL(ctx context.Context, distsDir, distributionURL, expectedSum string, unpack bool, reporter Reporter) error {
+	version, edition, err := ParseDistributionURL(distributionURL)
+	if err != nil {
+		return err
+	}
+
+	targetDir, err := WrapperDistributionDir(distsDir, distributionURL)
+	if err != nil {
+		return err
+	}
+	reportMessage(reporter, T("Gradle %s %s版 安装目录: %s\n", version, edition, targetDir))
+
+	zipPath := filepath.Join(targetDir, fmt.Sprintf("gradle-%s-%s.zip", version, edition))
+	if _, err := os.Stat(wrapperMarkerPath(zipPath)); err == nil {
+		reportMessage(reporter, T("Gradle %s %s版 已安装，无需处理\n", version, edition))
+		return nil
+	}
+
+	if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		return fmt.Errorf(T("创建安装目录失败: %v"), err)
+	}
+
+	if err := CopyGradleToTarget(ctx, version, edition, targetDir, expectedSum, reporter); err != nil {
+		return err
+	}
+
+	if unpack {
+		return UnpackGradleDistribution(ctx, version, edition, targetDir, reporter)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// 与Gradle Wrapper（PathAssembler）计算的目录名一致
+func TestWrapperURLHash(t *testing.T) {
+	url := "https://services.gradle.org/distributions/gradle-8.7-bin.zip"
+	if got, want := wrapperURLHash(url), "bhs2wmbdwecv87pi65oeuq5iu"; got != want {
+		t.Errorf("wrapperURLHash(%q) = %q, want %q", url, got, want)
+	}
+}
+
+func TestWrapperDistributionDir(t *testing.T) {
+	dir, err := WrapperDistributionDir("dists", OfficialDistributionURL("8.7", "bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("dists", "gradle-8.7-bin", "bhs2wmbdwecv87pi65oeuq5iu"); dir != want {
+		t.Errorf("WrapperDistributionDir = %q, want %q", dir, want)
+	}
+}
+
+func TestParseDistributionURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		version string
+		edition string
+		wantErr bool
+	}{
+		{"https://services.gradle.org/distributions/gradle-8.7-bin.zip", "8.7", "bin", false},
+		{"https://services.gradle.org/distributions/gradle-7.6.4-all.zip", "7.6.4", "all", false},
+		{"https://mirrors.cloud.tencent.com/gradle/gradle-8.10.2-bin.zip?x=1", "8.10.2", "bin", false},
+		{"https://example.com/gradle.zip", "", "", true},
+		{"https://example.com/", "", "", true},
+	}
+	for _, tt := range tests {
+		version, edition, err := ParseDistributionURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDistributionURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if version != tt.version || edition != tt.edition {
+			t.Errorf("ParseDistributionURL(%q) = %q, %q, want %q, %q", tt.url, version, edition, tt.version, tt.edition)
+		}
+	}
+}
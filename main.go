@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"mcr_gradletools/lib"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -23,47 +28,436 @@ const (
 
 var currentUser, _ = user.Current()
 var GradlePath = filepath.Join(currentUser.HomeDir, ".mcreator", "gradle", "wrapper", "dists")
+var WorkspacesPath = filepath.Join(currentUser.HomeDir, "MCreatorWorkspaces")
+
+// 从命令行参数中预先读取 --lang，命令说明在构造App时就需要翻译
+func langFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "lang" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
 
 func main() {
+	// 先确定界面语言：--lang 优先，否则根据环境变量
+	if err := lib.SetLocale(langFromArgs(os.Args[1:])); err != nil {
+		lib.SetLocale(lib.DetectLocale())
+	}
+
 	app := &cli.App{
 		Name:  "MCr_gradletools",
-		Usage: "一款Go语言编写的MCreator Gradle工具",
+		Usage: lib.T("一款Go语言编写的MCreator Gradle工具"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   lib.T("对所有确认提示自动回答\"是\"，用于脚本等非交互环境"),
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   lib.T("输出格式: text (文本)、json (结构化结果，进度信息输出到标准错误) 或 jsonl (每行一个事件的JSON Lines)"),
+				Value:   OutputText,
+			},
+			&cli.StringFlag{
+				Name:  "lang",
+				Usage: lib.T("界面语言: zh-CN 或 en（默认根据 LANG/LC_ALL 环境变量）"),
+			},
+			&cli.StringFlag{
+				Name:  "proxy",
+				Usage: lib.T("所有网络请求使用的代理，如 http://127.0.0.1:7890 或 socks5://127.0.0.1:1080，direct 表示不使用代理（默认读取配置文件和 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 环境变量）"),
+			},
+			&cli.StringSliceFlag{
+				Name:  "ca-cert",
+				Usage: lib.T("额外信任的CA证书文件（PEM格式，可多次指定），用于使用公司内部CA签发证书的镜像源"),
+			},
+			&cli.StringFlag{
+				Name:  "client-cert",
+				Usage: lib.T("mTLS客户端证书文件（PEM格式），需同时指定 --client-key"),
+			},
+			&cli.StringFlag{
+				Name:  "client-key",
+				Usage: lib.T("mTLS客户端证书的私钥文件（PEM格式）"),
+			},
+			&cli.BoolFlag{
+				Name:  "insecure",
+				Usage: lib.T("不校验服务器的TLS证书（不安全，仅用于排查问题）"),
+			},
+		},
+		// 退出码由main统一处理
+		ExitErrHandler: func(c *cli.Context, err error) {},
+		Before: func(c *cli.Context) error {
+			assumeYes = c.Bool("yes")
+			if c.IsSet("lang") {
+				if err := lib.SetLocale(c.String("lang")); err != nil {
+					return err
+				}
+			}
+			switch outputFormat = c.String("output"); outputFormat {
+			case OutputText:
+				reporter = lib.NewTerminalReporter(os.Stdout)
+			case OutputJSON:
+				// 标准输出只用于JSON结果，进度信息输出到标准错误
+				outputJSON = true
+				reporter = lib.NewTerminalReporter(os.Stderr)
+			case OutputJSONL:
+				outputJSON = true
+				reporter = lib.NewJSONLinesReporter(os.Stdout)
+			default:
+				return fmt.Errorf(lib.T("不支持的输出格式: %s"), c.String("output"))
+			}
+
+			// 读取用户配置文件（不存在时使用内置设置）
+			cfg, err := lib.LoadConfig()
+			if err != nil {
+				return err
+			}
+			lib.ApplyConfig(cfg)
+
+			if c.IsSet("proxy") {
+				if err := lib.SetProxy(c.String("proxy")); err != nil {
+					return err
+				}
+			}
+
+			// 命令行指定的CA证书追加到配置文件中的证书之后，其余选项覆盖配置文件
+			tlsConfig := cfg.TLS
+			tlsConfig.CACerts = append(tlsConfig.CACerts, c.StringSlice("ca-cert")...)
+			if c.IsSet("client-cert") {
+				tlsConfig.ClientCert = c.String("client-cert")
+			}
+			if c.IsSet("client-key") {
+				tlsConfig.ClientKey = c.String("client-key")
+			}
+			if c.Bool("insecure") {
+				tlsConfig.Insecure = true
+			}
+			if err := lib.SetTLS(tlsConfig); err != nil {
+				return err
+			}
+			if tlsConfig.Insecure {
+				// 始终输出到标准错误，JSON模式下也不会被忽略
+				fmt.Fprintln(os.Stderr, lib.T("⚠️⚠️⚠️ 警告: 已关闭TLS证书校验！连接可能被窃听或篡改，下载的发行包和校验和都可能被替换。请勿在日常使用中开启 --insecure 或 tls.insecure。"))
+			}
+			return nil
+		},
 		Commands: []*cli.Command{
+			{
+				Name:  "cache",
+				Usage: lib.T("查看、校验、导出和导入Gradle下载缓存"),
+				Subcommands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: lib.T("列出缓存的Gradle发行包及其来源、大小和使用时间"),
+						Action: func(c *cli.Context) error {
+							entries, err := lib.ListCacheEntries()
+							if err != nil {
+								return err
+							}
+
+							if outputJSON {
+								if entries == nil {
+									entries = []lib.CacheEntry{}
+								}
+								return printJSON(map[string]any{"cache_dir": lib.GetCacheDir(), "entries": entries})
+							}
+
+							if len(entries) == 0 {
+								fmt.Println(lib.T("缓存目录为空"))
+								return nil
+							}
+
+							w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+							fmt.Fprintln(w, lib.T("版本\t大小\t镜像源\t下载时间\t最后使用"))
+							for _, entry := range entries {
+								mirror := entry.Mirror
+								if mirror == "" {
+									mirror = "-"
+								}
+								fmt.Fprintf(w, lib.T("%s %s版\t%s\t%s\t%s\t%s\n"),
+									entry.Version, entry.Edition,
+									lib.FormatBytes(entry.Size),
+									mirror,
+									entry.DownloadedAt.Local().Format("2006-01-02 15:04"),
+									entry.LastUsedAt.Local().Format("2006-01-02 15:04"))
+							}
+							w.Flush()
+							return nil
+						},
+					},
+					{
+						Name:  "verify",
+						Usage: lib.T("重新校验缓存中的每个发行包，找出损坏、丢失和多余的文件"),
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "remove",
+								Usage: lib.T("删除损坏和多余的文件，并从索引中移除失效的记录"),
+							},
+						},
+						Action: func(c *cli.Context) error {
+							checks, err := lib.VerifyCache(c.Context, c.Bool("remove"), reporter)
+							if err != nil {
+								return fmt.Errorf(lib.T("校验缓存失败: %v"), err)
+							}
+
+							// 统计仍未解决的问题
+							ok, problems := 0, 0
+							for _, check := range checks {
+								switch {
+								case check.Status == lib.CacheOK:
+									ok++
+								case !check.Removed:
+									problems++
+								}
+							}
+
+							if outputJSON {
+								if checks == nil {
+									checks = []lib.CacheCheck{}
+								}
+								printJSON(map[string]any{"results": checks})
+							} else {
+								for _, check := range checks {
+									if check.Status == lib.CacheOK {
+										continue
+									}
+									line := fmt.Sprintf("  [%s] %s", check.Status, check.File)
+									if check.Detail != "" {
+										line += ": " + check.Detail
+									}
+									if check.Removed {
+										line += lib.T("（已清理）")
+									}
+									fmt.Println(line)
+								}
+								fmt.Printf(lib.T("总计: %d 个文件，%d 个完整，%d 个问题未处理\n"), len(checks), ok, problems)
+							}
+
+							switch {
+							case len(checks) == 0:
+								return cli.Exit("", ExitNothingToDo)
+							case problems > 0:
+								return cli.Exit(errorMessage(errors.New(lib.T("缓存中存在问题，可使用 --remove 清理"))), ExitFailure)
+							}
+							return nil
+						},
+					},
+					{
+						Name:  "export",
+						Usage: lib.T("将缓存中的发行包打包为离线包（含校验和清单），分发给无法联网的电脑"),
+						Flags: []cli.Flag{
+							&cli.StringSliceFlag{
+								Name:  "versions",
+								Usage: lib.T("要导出的Gradle版本号，以逗号分隔（例如: 8.7,8.14.2），默认导出全部缓存"),
+							},
+							&cli.StringFlag{
+								Name:    "edition",
+								Aliases: []string{"e"},
+								Usage:   lib.T("只导出指定的版本类型: bin 或 all"),
+							},
+							&cli.StringFlag{
+								Name:     "output",
+								Aliases:  []string{"o"},
+								Usage:    lib.T("离线包的保存路径 (例如: bundle.tar)"),
+								Required: true,
+							},
+						},
+						Action: func(c *cli.Context) error {
+							output := c.String("output")
+							entries, err := lib.ExportBundle(c.Context, c.StringSlice("versions"), c.String("edition"), output, reporter)
+							if err != nil {
+								return fmt.Errorf(lib.T("导出离线包失败: %v"), err)
+							}
+
+							if outputJSON {
+								return printJSON(map[string]any{"output": output, "entries": entries})
+							}
+
+							var size int64
+							for _, entry := range entries {
+								size += entry.Size
+							}
+							fmt.Printf(lib.T("✅ 已导出 %d 个发行包到 %s（%s）\n"), len(entries), output, lib.FormatBytes(size))
+							return nil
+						},
+					},
+					{
+						Name:      "import",
+						Usage:     lib.T("导入 cache export 生成的离线包，按清单校验后放入缓存，可同时安装到MCreator的dists目录"),
+						ArgsUsage: lib.T("<离线包>"),
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "dists",
+								Usage: lib.T("同时安装到MCreator的dists目录（按官方distributionUrl计算安装目录）"),
+							},
+							&cli.StringFlag{
+								Name:    "path",
+								Aliases: []string{"p"},
+								Usage:   lib.T("MCreator Gradle目录路径"),
+								Value:   GradlePath,
+							},
+							&cli.BoolFlag{
+								Name:    "unpack",
+								Aliases: []string{"u"},
+								Usage:   lib.T("安装到dists目录时按Gradle Wrapper的方式直接解压"),
+							},
+						},
+						Action: func(c *cli.Context) error {
+							if c.NArg() != 1 {
+								return errors.New(lib.T("请指定一个离线包"))
+							}
+							// 离线包中已包含所需的全部文件，不访问网络
+							lib.SetOffline(true)
+
+							distsDir := ""
+							if c.Bool("dists") {
+								distsDir = c.String("path")
+							}
+							results, err := lib.ImportBundle(c.Context, c.Args().First(), distsDir, c.Bool("unpack"), reporter)
+							if err != nil && results == nil {
+								return fmt.Errorf(lib.T("导入离线包失败: %v"), err)
+							}
+
+							succeeded, failed := 0, 0
+							for _, result := range results {
+								switch result.Status {
+								case lib.ImportOK, lib.ImportExists:
+									succeeded++
+								default:
+									failed++
+								}
+							}
+
+							if outputJSON {
+								output := map[string]any{"results": results}
+								if err != nil {
+									output["error"] = err.Error()
+								}
+								printJSON(output)
+							} else {
+								fmt.Printf(lib.T("总计: %d 个文件，%d 个已导入或已存在，%d 个失败\n"), len(results), succeeded, failed)
+							}
+							return summaryExit(succeeded, failed, err)
+						},
+					},
+				},
+			},
 			{
 				Name:  "check-mirrors",
-				Usage: "检查所有镜像源的可用性",
+				Usage: lib.T("测试所有镜像源的可用性和速度，并按速度排名"),
 				Action: func(c *cli.Context) error {
-					fmt.Println("正在检查镜像源可用性...")
+					printMessage(lib.T("正在并发测试镜像源速度..."))
 
-					// 调用CheckAllMirrors函数
-					availableMirrors, unavailableMirrors := lib.CheckAllMirrors()
+					// 调用BenchmarkMirrors函数，结果已按速度排序
+					results, err := lib.BenchmarkMirrors(c.Context)
+					if c.Context.Err() != nil {
+						return err
+					}
+					if err != nil {
+						printMessage(fmt.Sprintf("⚠️ %v\n", err))
+					}
 
-					fmt.Printf("\n✅ 可用镜像源 (%d个):\n", len(availableMirrors))
-					for _, mirror := range availableMirrors {
-						fmt.Printf("  ✓ %s\n", mirror)
+					if outputJSON {
+						type mirrorResult struct {
+							Rank       int     `json:"rank,omitempty"`
+							Name       string  `json:"name"`
+							Available  bool    `json:"available"`
+							TTFBMillis int64   `json:"ttfb_ms,omitempty"`
+							Throughput float64 `json:"throughput_bytes_per_sec,omitempty"`
+							Error      string  `json:"error,omitempty"`
+						}
+						output := make([]mirrorResult, 0, len(results))
+						available := 0
+						for i, result := range results {
+							item := mirrorResult{Name: result.Name, Available: result.Available, Error: result.Error}
+							if result.Available {
+								available++
+								item.Rank = i + 1
+								item.TTFBMillis = result.TTFB.Milliseconds()
+								item.Throughput = result.Throughput
+							}
+							output = append(output, item)
+						}
+						if err := printJSON(map[string]any{"mirrors": output}); err != nil {
+							return err
+						}
+						return summaryExit(available, len(results)-available, nil)
 					}
 
-					fmt.Printf("\n❌ 不可用镜像源 (%d个):\n", len(unavailableMirrors))
-					for _, mirror := range unavailableMirrors {
-						fmt.Printf("  ✗ %s\n", mirror)
+					available := 0
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, lib.T("\n排名\t镜像源\t首字节\t速度\t状态"))
+					for i, result := range results {
+						if !result.Available {
+							fmt.Fprintf(w, "-\t%s\t-\t-\t✗ %s\n", result.Name, result.Error)
+							continue
+						}
+						available++
+						fmt.Fprintf(w, lib.T("%d\t%s\t%v\t%s/s\t✓ 可用\n"),
+							i+1,
+							result.Name,
+							result.TTFB.Round(time.Millisecond),
+							lib.FormatBytes(int64(result.Throughput)))
 					}
+					w.Flush()
 
-					fmt.Printf("\n总计: %d个镜像源，%d个可用，%d个不可用\n",
-						len(availableMirrors)+len(unavailableMirrors),
-						len(availableMirrors),
-						len(unavailableMirrors))
+					fmt.Printf(lib.T("\n总计: %d个镜像源，%d个可用，%d个不可用\n"),
+						len(results), available, len(results)-available)
+					if available > 0 {
+						fmt.Printf(lib.T("最快镜像源: %s（后续下载将优先使用）\n"), results[0].Name)
+					}
 
-					return nil
+					return summaryExit(available, len(results)-available, nil)
 				},
 			},
 			{
 				Name:  "clear-cache",
-				Usage: "清理Gradle下载缓存",
+				Usage: lib.T("清理Gradle下载缓存"),
 				Flags: []cli.Flag{
 					&cli.BoolFlag{
 						Name:    "list",
 						Aliases: []string{"l"},
-						Usage:   "仅列出缓存文件，不删除",
+						Usage:   lib.T("仅列出缓存文件，不删除"),
+					},
+					&cli.StringFlag{
+						Name:  "version",
+						Usage: lib.T("只清理指定的Gradle版本"),
+					},
+					&cli.StringFlag{
+						Name:  "edition",
+						Usage: lib.T("只清理指定的版本类型: bin 或 all"),
+					},
+					&cli.StringFlag{
+						Name:  "older-than",
+						Usage: lib.T("清理超过指定时长未使用的发行包 (例如: 30d、2w、12h)"),
+					},
+					&cli.IntFlag{
+						Name:  "keep-latest",
+						Usage: lib.T("每种版本类型只保留版本号最新的N个发行包"),
+					},
+					&cli.StringFlag{
+						Name:  "max-size",
+						Usage: lib.T("缓存总大小上限 (例如: 2GB)，超出时优先清理最久未使用的发行包"),
+					},
+					&cli.StringSliceFlag{
+						Name:  "workspace",
+						Usage: lib.T("按策略清理时保留这些MCreator工作区使用的Gradle版本（可多次指定，默认为 %s 下的所有工作区）", WorkspacesPath),
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: lib.T("只显示将要删除的文件和可释放的空间，不实际删除"),
 					},
 				},
 				Action: func(c *cli.Context) error {
@@ -73,144 +467,999 @@ func main() {
 						// 仅列出缓存文件
 						files, err := lib.ListCacheFiles()
 						if err != nil {
-							return fmt.Errorf("获取缓存文件列表失败: %v", err)
+							return fmt.Errorf(lib.T("获取缓存文件列表失败: %v"), err)
+						}
+
+						if outputJSON {
+							if files == nil {
+								files = []string{}
+							}
+							return printJSON(map[string]any{"cache_dir": lib.GetCacheDir(), "files": files})
 						}
 
 						if len(files) == 0 {
-							fmt.Println("缓存目录为空")
+							fmt.Println(lib.T("缓存目录为空"))
 							return nil
 						}
 
-						fmt.Printf("缓存目录 (%s) 中的文件:\n", lib.GetCacheDir())
+						fmt.Printf(lib.T("缓存目录 (%s) 中的文件:\n"), lib.GetCacheDir())
 						for i, file := range files {
 							fmt.Printf("  %d. %s\n", i+1, file)
 						}
-						fmt.Printf("总计: %d 个文件\n", len(files))
+						fmt.Printf(lib.T("总计: %d 个文件\n"), len(files))
 						return nil
 					}
 
+					// 指定了筛选条件或清理策略时只清理符合条件的发行包
+					for _, name := range []string{"version", "edition", "older-than", "keep-latest", "max-size"} {
+						if c.IsSet(name) {
+							return pruneCache(c)
+						}
+					}
+
 					// 清理缓存
-					fmt.Println("正在清理Gradle下载缓存...")
+					printMessage(lib.T("正在清理Gradle下载缓存..."))
 
 					// 先列出缓存文件
 					files, err := lib.ListCacheFiles()
 					if err != nil {
-						return fmt.Errorf("获取缓存文件列表失败: %v", err)
+						return fmt.Errorf(lib.T("获取缓存文件列表失败: %v"), err)
 					}
 
 					if len(files) == 0 {
-						fmt.Println("缓存目录为空，无需清理")
-						return nil
+						printMessage(lib.T("缓存目录为空，无需清理"))
+						if outputJSON {
+							printJSON(map[string]any{"deleted": []string{}})
+						}
+						return cli.Exit("", ExitNothingToDo)
 					}
 
-					fmt.Printf("即将删除 %d 个缓存文件:\n", len(files))
+					printMessage(lib.T("即将删除 %d 个缓存文件:\n", len(files)))
+					var reclaimed int64
 					for i, file := range files {
-						fmt.Printf("  %d. %s\n", i+1, file)
+						printMessage(fmt.Sprintf("  %d. %s\n", i+1, file))
+						if info, err := os.Stat(filepath.Join(lib.GetCacheDir(), file)); err == nil {
+							reclaimed += info.Size()
+						}
 					}
+					printMessage(lib.T("可释放空间: %s\n", lib.FormatBytes(reclaimed)))
 
-					// 确认删除
-					fmt.Print("\n确认删除这些文件吗？(y/N): ")
-					var confirm string
-					fmt.Scanln(&confirm)
+					if c.Bool("dry-run") {
+						printMessage(lib.T("预演模式，未删除任何文件"))
+						if outputJSON {
+							printJSON(map[string]any{"dry_run": true, "files": files, "reclaimed_bytes": reclaimed})
+						}
+						return nil
+					}
 
-					if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
-						fmt.Println("操作已取消")
+					// 确认删除
+					ok, err := confirm(lib.T("\n确认删除这些文件吗？(y/N): "))
+					if err != nil {
+						return err
+					}
+					if !ok {
+						fmt.Println(lib.T("操作已取消"))
 						return nil
 					}
 
 					// 执行清理
-					if err := lib.ClearCache(); err != nil {
-						return fmt.Errorf("清理缓存失败: %v", err)
+					deleted, err := lib.ClearCache(reporter)
+					if outputJSON {
+						if deleted == nil {
+							deleted = []string{}
+						}
+						printJSON(map[string]any{"deleted": deleted})
+					}
+					if err != nil {
+						return summaryExit(len(deleted), 1, fmt.Errorf(lib.T("清理缓存失败: %v"), err))
 					}
 
-					fmt.Println("✅ 缓存清理完成")
+					printMessage(lib.T("✅ 缓存清理完成"))
 					return nil
 				},
 			},
+			{
+				Name:  "config",
+				Usage: lib.T("管理配置文件（自定义镜像源等）"),
+				Subcommands: []*cli.Command{
+					{
+						Name:  "init",
+						Usage: lib.T("以内置镜像源生成配置文件"),
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "force",
+								Usage: lib.T("覆盖已存在的配置文件"),
+							},
+						},
+						Action: func(c *cli.Context) error {
+							configPath, err := lib.InitConfig(c.Bool("force"))
+							if err != nil {
+								return err
+							}
+							if outputJSON {
+								return printJSON(map[string]string{"config_path": configPath})
+							}
+							fmt.Printf(lib.T("✅ 已生成配置文件: %s\n"), configPath)
+							return nil
+						},
+					},
+					{
+						Name:  "path",
+						Usage: lib.T("显示配置文件路径"),
+						Action: func(c *cli.Context) error {
+							if outputJSON {
+								return printJSON(map[string]string{"config_path": lib.GetConfigPath()})
+							}
+							fmt.Println(lib.GetConfigPath())
+							return nil
+						},
+					},
+					{
+						Name:  "mirrors",
+						Usage: lib.T("列出当前生效的镜像源"),
+						Action: func(c *cli.Context) error {
+							if outputJSON {
+								// 地址和代理中的凭据已隐去，认证方式只列出环境变量名
+								type mirrorInfo struct {
+									Name     string `json:"name"`
+									URL      string `json:"url"`
+									Disabled bool   `json:"disabled"`
+									Proxy    string `json:"proxy,omitempty"`
+									Auth     string `json:"auth,omitempty"`
+								}
+								mirrors := []mirrorInfo{}
+								for _, mirror := range lib.GetMirrors() {
+									info := mirrorInfo{
+										Name:     mirror.Name,
+										URL:      lib.RedactURL(mirror.URL),
+										Disabled: mirror.Disabled,
+										Proxy:    lib.RedactProxy(mirror.Proxy),
+									}
+									if mirror.Auth != nil {
+										info.Auth = mirror.Auth.String()
+									}
+									mirrors = append(mirrors, info)
+								}
+								return printJSON(map[string]any{"config_path": lib.GetConfigPath(), "mirrors": mirrors})
+							}
+
+							for i, mirror := range lib.GetMirrors() {
+								status := lib.T("启用")
+								if mirror.Disabled {
+									status = lib.T("停用")
+								}
+								fmt.Printf("  %d. [%s] %s\n     %s\n", i+1, status, mirror.Name, lib.RedactURL(mirror.URL))
+								if mirror.Proxy != "" {
+									fmt.Printf(lib.T("     代理: %s\n"), lib.RedactProxy(mirror.Proxy))
+								}
+								if mirror.Auth != nil {
+									fmt.Printf(lib.T("     认证: %s\n"), mirror.Auth)
+								}
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "dists",
+				Usage: lib.T("管理MCreator的Gradle Wrapper dists目录"),
+				Subcommands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: lib.T("列出已安装的Gradle版本及其状态和大小"),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "path",
+								Aliases: []string{"p"},
+								Usage:   lib.T("MCreator Gradle目录路径"),
+								Value:   GradlePath,
+							},
+						},
+						Action: func(c *cli.Context) error {
+							entries, err := lib.ListDists(c.String("path"))
+							if err != nil {
+								return err
+							}
+
+							if outputJSON {
+								if entries == nil {
+									entries = []lib.DistEntry{}
+								}
+								return printJSON(map[string]any{"dists_dir": c.String("path"), "dists": entries})
+							}
+
+							if len(entries) == 0 {
+								fmt.Println(lib.T("dists目录中没有Gradle发行包"))
+								return nil
+							}
+
+							var total int64
+							w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+							fmt.Fprintln(w, lib.T("版本\t状态\t大小\t目录\t说明"))
+							for _, entry := range entries {
+								total += entry.Size
+								fmt.Fprintf(w, lib.T("%s %s版\t%s\t%s\t%s\t%s\n"),
+									entry.Version, entry.Edition,
+									entry.State,
+									lib.FormatBytes(entry.Size),
+									filepath.Base(entry.Dir),
+									entry.Detail)
+							}
+							w.Flush()
+							fmt.Printf(lib.T("\n总计: %d 个发行包，%s\n"), len(entries), lib.FormatBytes(total))
+							return nil
+						},
+					},
+					{
+						Name:  "prune",
+						Usage: lib.T("删除没有被任何已知工作区使用的Gradle版本"),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "path",
+								Aliases: []string{"p"},
+								Usage:   lib.T("MCreator Gradle目录路径"),
+								Value:   GradlePath,
+							},
+							&cli.StringSliceFlag{
+								Name:  "workspace",
+								Usage: lib.T("仍在使用的MCreator工作区目录（可多次指定，默认为 %s 下的所有工作区）", WorkspacesPath),
+							},
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: lib.T("只显示将要删除的文件和可释放的空间，不实际删除"),
+							},
+						},
+						Action: func(c *cli.Context) error {
+							workspaces := c.StringSlice("workspace")
+							if len(workspaces) == 0 {
+								workspaces = lib.FindWorkspaces(WorkspacesPath)
+							}
+							// 找不到工作区时所有版本都会被视为未使用，为避免误删直接停止
+							if len(workspaces) == 0 {
+								return errors.New(lib.T("未找到任何MCreator工作区，请使用 --workspace 指定"))
+							}
+
+							unused, err := lib.UnusedDists(c.String("path"), workspaces)
+							if err != nil {
+								return err
+							}
+
+							var reclaimed int64
+							for _, entry := range unused {
+								reclaimed += entry.Size
+							}
+
+							if len(unused) == 0 {
+								printMessage(lib.T("所有Gradle版本都在使用中，无需清理"))
+								if outputJSON {
+									printJSON(map[string]any{"dry_run": c.Bool("dry-run"), "dists": []lib.DistEntry{}, "deleted": []string{}, "reclaimed_bytes": 0})
+								}
+								return cli.Exit("", ExitNothingToDo)
+							}
+
+							printMessage(lib.T("以下 %d 个Gradle版本没有被工作区使用:\n", len(unused)))
+							for i, entry := range unused {
+								printMessage(fmt.Sprintf("  %d. %s (%s, %s)\n", i+1,
+									filepath.Join(filepath.Base(filepath.Dir(entry.Dir)), filepath.Base(entry.Dir)),
+									entry.State, lib.FormatBytes(entry.Size)))
+							}
+							printMessage(lib.T("可释放空间: %s\n", lib.FormatBytes(reclaimed)))
+
+							if c.Bool("dry-run") {
+								printMessage(lib.T("预演模式，未删除任何文件"))
+								if outputJSON {
+									printJSON(map[string]any{"dry_run": true, "dists": unused, "deleted": []string{}, "reclaimed_bytes": reclaimed})
+								}
+								return nil
+							}
+
+							ok, err := confirm(lib.T("\n确认删除这些文件吗？(y/N): "))
+							if err != nil {
+								return err
+							}
+							if !ok {
+								fmt.Println(lib.T("操作已取消"))
+								return nil
+							}
+
+							removed, err := lib.RemoveDists(unused, reporter)
+							if outputJSON {
+								if removed == nil {
+									removed = []string{}
+								}
+								printJSON(map[string]any{"dry_run": false, "dists": unused, "deleted": removed, "reclaimed_bytes": reclaimed})
+							}
+							if err != nil {
+								return summaryExit(len(removed), len(unused)-len(removed), err)
+							}
+
+							printMessage(lib.T("✅ 清理完成"))
+							return nil
+						},
+					},
+					{
+						Name:  "repair",
+						Usage: lib.T("重新下载并复制状态异常的Gradle版本"),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "path",
+								Aliases: []string{"p"},
+								Usage:   lib.T("MCreator Gradle目录路径"),
+								Value:   GradlePath,
+							},
+							&cli.BoolFlag{
+								Name:    "unpack",
+								Aliases: []string{"u"},
+								Usage:   lib.T("按Gradle Wrapper的方式直接解压安装，无需MCreator再次联网"),
+							},
+							&cli.StringSliceFlag{
+								Name:  "workspace",
+								Usage: lib.T("读取distributionSha256Sum的MCreator工作区目录（可多次指定，默认为 %s 下的所有工作区）", WorkspacesPath),
+							},
+							&cli.StringSliceFlag{
+								Name:    "mirror",
+								Aliases: []string{"m"},
+								Usage:   lib.T("仅使用指定的镜像源（镜像源名称或含 {{version}} 的URL模板，可多次指定）"),
+							},
+							&cli.IntFlag{
+								Name:    "jobs",
+								Aliases: []string{"j"},
+								Usage:   lib.T("同时处理的Gradle版本数量"),
+								Value:   lib.DefaultJobs,
+							},
+							&cli.BoolFlag{
+								Name:    "force",
+								Aliases: []string{"f"},
+								Usage:   lib.T("即使.lck/.part文件正被MCreator或Gradle进程使用也强制删除"),
+							},
+							&cli.DurationFlag{
+								Name:  "wait",
+								Usage: lib.T("临时文件正被其他进程使用时，等待其释放的最长时间（如 5m），默认不等待"),
+							},
+							&cli.BoolFlag{
+								Name:  "offline",
+								Usage: lib.T("离线模式，只使用缓存中的发行包，不访问网络"),
+							},
+						},
+						Action: func(c *cli.Context) error {
+							if err := lib.UseMirrors(c.StringSlice("mirror")); err != nil {
+								return err
+							}
+							lib.SetOffline(c.Bool("offline"))
+
+							entries, err := lib.ListDists(c.String("path"))
+							if err != nil {
+								return err
+							}
+							opts := processOptions(c)
+							var broken []lib.DistEntry
+							skipped := []lib.GradleResult{}
+							for _, entry := range entries {
+								switch {
+								case entry.State == lib.DistLocked && !opts.Force && opts.Wait == 0:
+									// 通常是MCreator或Gradle正在使用，不算作失败
+									printMessage(lib.T("⏭️ 跳过Gradle %s %s版: %s（可使用 --force 强制修复或 --wait 等待）\n",
+										entry.Version, entry.Edition, entry.Detail))
+									skipped = append(skipped, lib.GradleResult{
+										Version:   entry.Version,
+										Edition:   entry.Edition,
+										TargetDir: entry.Dir,
+										Status:    lib.StatusSkipped,
+									})
+								case entry.State == lib.DistCorrupt || entry.State == lib.DistLocked ||
+									// 只解压安装时，未解压的发行包也需要处理
+									(entry.State == lib.DistIncomplete && (c.Bool("unpack") || !entry.NeedsUnpack)):
+									broken = append(broken, entry)
+								}
+							}
+
+							if len(broken) == 0 {
+								printMessage(lib.T("没有需要修复的Gradle版本"))
+								if outputJSON {
+									printJSON(map[string]any{"results": skipped})
+								}
+								return cli.Exit("", ExitNothingToDo)
+							}
+
+							workspaces := c.StringSlice("workspace")
+							if len(workspaces) == 0 {
+								workspaces = lib.FindWorkspaces(WorkspacesPath)
+							}
+
+							printMessage(lib.T("找到 %d 个需要修复的Gradle版本:\n", len(broken)))
+							results := lib.RepairDists(c.Context, c.String("path"), broken, workspaces, opts, reporter)
+
+							succeeded, failed := 0, 0
+							for _, result := range results {
+								if result.Status == lib.StatusOK {
+									succeeded++
+								} else {
+									failed++
+								}
+							}
+
+							if outputJSON {
+								printJSON(map[string]any{"results": append(results, skipped...)})
+							}
+							if failed > 0 {
+								return summaryExit(succeeded, failed, fmt.Errorf(lib.T("%d 个Gradle版本修复失败"), failed))
+							}
+							return nil
+						},
+					},
+				},
+			},
 			{
 				Name:  "download",
-				Usage: "下载指定版本的Gradle",
+				Usage: lib.T("下载指定版本的Gradle"),
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:     "version",
 						Aliases:  []string{"v"},
-						Usage:    "Gradle版本号 (例如: 8.7)",
+						Usage:    lib.T("Gradle版本号 (例如: 8.7、8.14.2、9.0-rc-1)"),
 						Required: true,
 					},
 					&cli.StringFlag{
 						Name:    "edition",
 						Aliases: []string{"e"},
-						Usage:   "Gradle版本类型: bin (二进制版) 或 all (完整版)",
+						Usage:   lib.T("Gradle版本类型: bin (二进制版) 或 all (完整版)"),
 						Value:   "bin",
 					},
+					&cli.StringSliceFlag{
+						Name:    "mirror",
+						Aliases: []string{"m"},
+						Usage:   lib.T("仅使用指定的镜像源（镜像源名称或含 {{version}} 的URL模板，可多次指定）"),
+					},
 				},
 				Action: func(c *cli.Context) error {
 					version := c.String("version")
 					edition := c.String("edition")
 
+					// 下载前检查版本号和版本类型
+					if _, err := lib.ParseGradleVersion(version); err != nil {
+						return err
+					}
+					if err := lib.ValidateEdition(edition); err != nil {
+						return err
+					}
+
+					if err := lib.UseMirrors(c.StringSlice("mirror")); err != nil {
+						return err
+					}
+
 					// 调用DownloadGradle函数
-					err := lib.DownloadGradle(version, edition)
+					path, err := lib.DownloadGradle(c.Context, version, edition, "", reporter)
 					if err != nil {
-						return fmt.Errorf("下载Gradle失败: %v", err)
+						return fmt.Errorf(lib.T("下载Gradle失败: %v"), err)
+					}
+
+					if outputJSON {
+						sum, _ := lib.CachedChecksum(version, edition)
+						return printJSON(map[string]any{
+							"version": version,
+							"edition": edition,
+							"path":    path,
+							"sha256":  sum,
+						})
 					}
 
-					fmt.Printf("Gradle %s %s版下载安装成功！\n", version, edition)
+					fmt.Printf(lib.T("Gradle %s %s版下载安装成功！\n"), version, edition)
 					return nil
 				},
 			},
 			{
 				Name:  "gradle",
-				Usage: "自动处理MCreator的Gradle下载问题",
+				Usage: lib.T("自动处理MCreator的Gradle下载问题"),
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:    "path",
 						Aliases: []string{"p"},
-						Usage:   "MCreator Gradle目录路径",
+						Usage:   lib.T("MCreator Gradle目录路径"),
 						Value:   GradlePath,
 					},
+					&cli.BoolFlag{
+						Name:    "unpack",
+						Aliases: []string{"u"},
+						Usage:   lib.T("按Gradle Wrapper的方式直接解压安装，无需MCreator再次联网"),
+					},
+					&cli.StringSliceFlag{
+						Name:    "workspace",
+						Aliases: []string{"w"},
+						Usage:   lib.T("读取distributionSha256Sum的MCreator工作区目录（可多次指定，默认为 %s 下的所有工作区）", WorkspacesPath),
+					},
+					&cli.StringSliceFlag{
+						Name:    "mirror",
+						Aliases: []string{"m"},
+						Usage:   lib.T("仅使用指定的镜像源（镜像源名称或含 {{version}} 的URL模板，可多次指定）"),
+					},
+					&cli.IntFlag{
+						Name:    "jobs",
+						Aliases: []string{"j"},
+						Usage:   lib.T("同时处理的Gradle版本数量"),
+						Value:   lib.DefaultJobs,
+					},
+					&cli.BoolFlag{
+						Name:    "force",
+						Aliases: []string{"f"},
+						Usage:   lib.T("即使.lck/.part文件正被MCreator或Gradle进程使用也强制删除"),
+					},
+					&cli.DurationFlag{
+						Name:  "wait",
+						Usage: lib.T("临时文件正被其他进程使用时，等待其释放的最长时间（如 5m），默认不等待"),
+					},
+					&cli.BoolFlag{
+						Name:  "offline",
+						Usage: lib.T("离线模式，只使用缓存中的发行包，不访问网络"),
+					},
 				},
 				Action: func(c *cli.Context) error {
 					gradlePath := c.String("path")
 
+					if err := lib.UseMirrors(c.StringSlice("mirror")); err != nil {
+						return err
+					}
+					lib.SetOffline(c.Bool("offline"))
+
 					// 调用ProcessMCreatorGradle函数
-					err := lib.ProcessMCreatorGradle(gradlePath)
+					// 未指定工作区时使用MCreator默认工作区目录下的所有工作区
+					workspaces := c.StringSlice("workspace")
+					if len(workspaces) == 0 {
+						workspaces = lib.FindWorkspaces(WorkspacesPath)
+					}
+
+					results, err := lib.ProcessMCreatorGradle(c.Context, gradlePath, workspaces, processOptions(c), reporter)
 					if err != nil {
-						return fmt.Errorf("处理MCreator Gradle失败: %v", err)
+						err = fmt.Errorf(lib.T("处理MCreator Gradle失败: %v"), err)
+					}
+
+					succeeded, failed := 0, 0
+					for _, result := range results {
+						switch result.Status {
+						case lib.StatusOK:
+							succeeded++
+						default:
+							failed++
+						}
+					}
+
+					if outputJSON {
+						if results == nil {
+							results = []lib.GradleResult{}
+						}
+						output := map[string]any{"results": results}
+						if err != nil {
+							output["error"] = err.Error()
+						}
+						printJSON(output)
+					}
+
+					return summaryExit(succeeded, failed, err)
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     lib.T("从本地ZIP文件或目录导入Gradle发行包到缓存，用于无法联网的电脑"),
+				ArgsUsage: lib.T("<ZIP文件或目录>"),
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return errors.New(lib.T("请指定一个ZIP文件或包含ZIP文件的目录"))
 					}
 
+					results, err := lib.ImportGradle(c.Context, c.Args().First(), reporter)
+					if err != nil && results == nil {
+						return fmt.Errorf(lib.T("导入失败: %v"), err)
+					}
+
+					succeeded, failed := 0, 0
+					for _, result := range results {
+						switch result.Status {
+						case lib.ImportOK, lib.ImportExists:
+							succeeded++
+						default:
+							failed++
+						}
+					}
+
+					if outputJSON {
+						output := map[string]any{"results": results}
+						if err != nil {
+							output["error"] = err.Error()
+						}
+						printJSON(output)
+					} else {
+						fmt.Printf(lib.T("总计: %d 个文件，%d 个已导入或已存在，%d 个失败\n"), len(results), succeeded, failed)
+					}
+
+					return summaryExit(succeeded, failed, err)
+				},
+			},
+			{
+				Name:  "install",
+				Usage: lib.T("按distributionUrl预先安装Gradle，无需先让MCreator构建失败"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "url",
+						Usage:    lib.T("gradle-wrapper.properties中的distributionUrl (例如: https://services.gradle.org/distributions/gradle-8.7-bin.zip)"),
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "path",
+						Aliases: []string{"p"},
+						Usage:   lib.T("MCreator Gradle目录路径"),
+						Value:   GradlePath,
+					},
+					&cli.BoolFlag{
+						Name:    "unpack",
+						Aliases: []string{"u"},
+						Usage:   lib.T("按Gradle Wrapper的方式直接解压安装"),
+					},
+					&cli.StringFlag{
+						Name:  "sha256",
+						Usage: lib.T("发行包的SHA-256校验和（gradle-wrapper.properties中的distributionSha256Sum）"),
+					},
+					&cli.StringSliceFlag{
+						Name:    "mirror",
+						Aliases: []string{"m"},
+						Usage:   lib.T("仅使用指定的镜像源（镜像源名称或含 {{version}} 的URL模板，可多次指定）"),
+					},
+				},
+				Action: func(c *cli.Context) error {
+					if err := lib.UseMirrors(c.StringSlice("mirror")); err != nil {
+						return err
+					}
+
+					// 调用InstallFromURL函数
+					err := lib.InstallFromURL(c.Context, c.String("path"), c.String("url"), strings.ToLower(c.String("sha256")), c.Bool("unpack"), reporter)
+					if err != nil {
+						return fmt.Errorf(lib.T("安装Gradle失败: %v"), err)
+					}
+
+					if outputJSON {
+						// 安装成功时URL已通过检查，这里不会再出错
+						version, edition, _ := lib.ParseDistributionURL(c.String("url"))
+						targetDir, _ := lib.WrapperDistributionDir(c.String("path"), c.String("url"))
+						return printJSON(map[string]any{
+							"version":    version,
+							"edition":    edition,
+							"target_dir": targetDir,
+							"unpacked":   c.Bool("unpack"),
+						})
+					}
+
+					fmt.Println(lib.T("✅ Gradle安装完成"))
 					return nil
 				},
 			},
+			{
+				Name:      "workspace",
+				Usage:     lib.T("读取MCreator工作区的Wrapper配置，检查所需Gradle版本是否已安装"),
+				ArgsUsage: lib.T("<工作区目录>..."),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "path",
+						Aliases: []string{"p"},
+						Usage:   lib.T("MCreator Gradle目录路径"),
+						Value:   GradlePath,
+					},
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return errors.New(lib.T("请指定至少一个MCreator工作区目录"))
+					}
+
+					// JSON模式下输出的单个工作区的检查结果
+					type workspaceResult struct {
+						Dir                   string `json:"dir"`
+						Version               string `json:"version,omitempty"`
+						Edition               string `json:"edition,omitempty"`
+						DistributionURL       string `json:"distribution_url,omitempty"`
+						DistributionSha256Sum string `json:"distribution_sha256,omitempty"`
+						InstallDir            string `json:"install_dir,omitempty"`
+						Status                string `json:"status"` // installed、cached、mismatch、missing 或 error
+						Error                 string `json:"error,omitempty"`
+					}
+
+					statusText := map[string]string{
+						"installed": lib.T("  ✅ 已安装"),
+						"cached":    lib.T("  📦 未安装，缓存中已有发行包（可使用 install 命令安装）"),
+						"mismatch":  lib.T("  ⚠️ 未安装，缓存中的发行包与distributionSha256Sum不一致"),
+						"missing":   lib.T("  ⚠️ 未安装，缓存中也没有发行包"),
+					}
+
+					// 逐个检查工作区，收集缺失的版本（去重）
+					var missing []lib.WorkspaceStatus
+					results := []workspaceResult{}
+					seen := make(map[string]bool)
+					for _, dir := range c.Args().Slice() {
+						status := lib.CheckWorkspace(c.String("path"), dir)
+						result := workspaceResult{Dir: dir}
+
+						if !outputJSON {
+							fmt.Printf("\n📁 %s\n", dir)
+						}
+						if status.Err != nil {
+							result.Status, result.Error = "error", status.Err.Error()
+							results = append(results, result)
+							if !outputJSON {
+								fmt.Printf("  ❌ %v\n", status.Err)
+							}
+							continue
+						}
+
+						result.Version, result.Edition = status.Version, status.Edition
+						result.DistributionURL = status.Properties.DistributionURL
+						result.DistributionSha256Sum = status.Properties.DistributionSha256Sum
+						result.InstallDir = status.InstallDir
+						if !outputJSON {
+							fmt.Printf(lib.T("  Gradle版本: %s %s版\n"), status.Version, status.Edition)
+							fmt.Printf("  distributionUrl: %s\n", status.Properties.DistributionURL)
+							if status.Properties.DistributionSha256Sum != "" {
+								fmt.Printf("  distributionSha256Sum: %s\n", status.Properties.DistributionSha256Sum)
+							}
+							if status.Properties.ZipStorePath != "" {
+								fmt.Printf("  zipStorePath: %s\n", status.Properties.ZipStorePath)
+							}
+							fmt.Printf(lib.T("  安装目录: %s\n"), status.InstallDir)
+						}
+
+						switch {
+						case status.Installed:
+							result.Status = "installed"
+						case status.Cached:
+							result.Status = "cached"
+						default:
+							if status.Mismatch {
+								result.Status = "mismatch"
+							} else {
+								result.Status = "missing"
+							}
+							key := status.Version + "-" + status.Edition
+							if !seen[key] {
+								seen[key] = true
+								missing = append(missing, status)
+							}
+						}
+						if !outputJSON {
+							fmt.Println(statusText[result.Status])
+						}
+						results = append(results, result)
+					}
+
+					// JSON模式下未指定--yes时只输出检查结果，不预先下载
+					if len(missing) == 0 || (outputJSON && !assumeYes) {
+						if outputJSON {
+							return printJSON(map[string]any{"workspaces": results, "downloads": []lib.GradleResult{}})
+						}
+						return nil
+					}
+
+					// 询问是否预先下载
+					if !outputJSON {
+						fmt.Printf(lib.T("\n有 %d 个Gradle版本尚未下载:\n"), len(missing))
+						for i, status := range missing {
+							fmt.Printf(lib.T("  %d. Gradle %s %s版\n"), i+1, status.Version, status.Edition)
+						}
+					}
+					ok, err := confirm(lib.T("\n是否现在预先下载？(y/N): "))
+					if err != nil {
+						return err
+					}
+					if !ok {
+						fmt.Println(lib.T("操作已取消"))
+						return nil
+					}
+
+					// 逐个下载，某个版本失败时继续下载其余版本
+					downloads := make([]lib.GradleResult, 0, len(missing))
+					succeeded, failed := 0, 0
+					for _, status := range missing {
+						download := lib.GradleResult{
+							Version:   status.Version,
+							Edition:   status.Edition,
+							TargetDir: lib.GetCacheDir(),
+						}
+						switch {
+						case c.Context.Err() != nil:
+							download.Status = lib.StatusSkipped
+						default:
+							_, err := lib.DownloadGradle(c.Context, status.Version, status.Edition, status.Properties.DistributionSha256Sum, reporter)
+							if err != nil {
+								download.Status, download.Error = lib.StatusFailed, err.Error()
+								printMessage(lib.T("❌ Gradle %s %s版 下载失败: %v\n", status.Version, status.Edition, err))
+								failed++
+							} else {
+								download.Status = lib.StatusOK
+								succeeded++
+							}
+						}
+						downloads = append(downloads, download)
+					}
+
+					switch {
+					case c.Context.Err() != nil:
+						err = c.Context.Err()
+					case failed > 0:
+						err = fmt.Errorf(lib.T("%d 个Gradle版本下载失败"), failed)
+					}
+
+					if outputJSON {
+						output := map[string]any{"workspaces": results, "downloads": downloads}
+						if err != nil {
+							output["error"] = err.Error()
+						}
+						printJSON(output)
+					} else if err == nil {
+						fmt.Println(lib.T("✅ 预先下载完成"))
+					}
+					return summaryExit(succeeded, failed, err)
+				},
+			},
 			{
 				Name:    "version",
 				Aliases: []string{"v", "ver"},
-				Usage:   "显示程序版本信息",
+				Usage:   lib.T("显示程序版本信息"),
 				Action: func(c *cli.Context) error {
-					fmt.Printf("%s 版本信息\n", AppName)
-					fmt.Printf("版本: %s\n", Version)
-					fmt.Printf("构建日期: %s\n", BuildDate)
-					fmt.Printf("Go版本: %s\n", GoVersion)
-					fmt.Printf("项目仓库: %s\n", Repository)
-					fmt.Println("\n一款专为MCreator设计的Gradle管理工具")
-					fmt.Println("作者: CreateCN")
+					if outputJSON {
+						return printJSON(map[string]string{
+							"name":       AppName,
+							"version":    Version,
+							"build_date": BuildDate,
+							"go_version": GoVersion,
+							"repository": Repository,
+						})
+					}
+
+					fmt.Printf(lib.T("%s 版本信息\n"), AppName)
+					fmt.Printf(lib.T("版本: %s\n"), Version)
+					fmt.Printf(lib.T("构建日期: %s\n"), BuildDate)
+					fmt.Printf(lib.T("Go版本: %s\n"), GoVersion)
+					fmt.Printf(lib.T("项目仓库: %s\n"), Repository)
+					fmt.Println(lib.T("\n一款专为MCreator设计的Gradle管理工具"))
+					fmt.Println(lib.T("作者: CreateCN"))
 					return nil
 				},
 			},
 		},
 		Action: func(c *cli.Context) error {
-			fmt.Println("MCr_gradletools - MCreator Gradle管理工具")
-			fmt.Println("使用 '--help' 查看可用命令")
-			fmt.Println("可用命令:")
-			fmt.Println("  check-mirrors - 检查镜像源可用性")
-			fmt.Println("  clear-cache   - 清理Gradle下载缓存")
-			fmt.Println("  download      - 下载指定版本的Gradle")
-			fmt.Println("  gradle        - 自动处理MCreator的Gradle下载问题")
-			fmt.Println("  version       - 显示程序版本信息")
+			fmt.Println(lib.T("MCr_gradletools - MCreator Gradle管理工具"))
+			fmt.Println(lib.T("使用 '--help' 查看可用命令"))
+			fmt.Println(lib.T("可用命令:"))
+			fmt.Println(lib.T("  cache         - 查看、校验、导出和导入Gradle下载缓存"))
+			fmt.Println(lib.T("  check-mirrors - 测试镜像源可用性和速度"))
+			fmt.Println(lib.T("  clear-cache   - 清理Gradle下载缓存"))
+			fmt.Println(lib.T("  config        - 管理配置文件（自定义镜像源等）"))
+			fmt.Println(lib.T("  dists         - 管理MCreator的Gradle Wrapper dists目录"))
+			fmt.Println(lib.T("  download      - 下载指定版本的Gradle"))
+			fmt.Println(lib.T("  gradle        - 自动处理MCreator的Gradle下载问题"))
+			fmt.Println(lib.T("  import        - 从本地ZIP文件或目录导入Gradle发行包"))
+			fmt.Println(lib.T("  install       - 按distributionUrl预先安装Gradle"))
+			fmt.Println(lib.T("  workspace     - 检查MCreator工作区所需的Gradle版本"))
+			fmt.Println(lib.T("  version       - 显示程序版本信息"))
 			return nil
 		},
 	}
-	err := app.Run(os.Args)
+	// Ctrl+C或SIGTERM时取消正在进行的下载、复制和解压，由各步骤清理未完成的文件
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		// 收到第一个信号后恢复默认处理，再次按Ctrl+C可强制退出
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := app.RunContext(ctx, os.Args)
+	if ctx.Err() != nil {
+		err = cli.Exit(lib.T("操作已中断"), ExitInterrupted)
+	}
+	handleExit(err)
+}
+
+// 按筛选条件和清理策略清理缓存（clear-cache 指定了 --version、--older-than 等选项时）
+func pruneCache(c *cli.Context) error {
+	opts := lib.CachePruneOptions{
+		Version:    c.String("version"),
+		Edition:    c.String("edition"),
+		KeepLatest: c.Int("keep-latest"),
+	}
+	if opts.Version != "" {
+		if _, err := lib.ParseGradleVersion(opts.Version); err != nil {
+			return err
+		}
+	}
+	if opts.Edition != "" {
+		if err := lib.ValidateEdition(opts.Edition); err != nil {
+			return err
+		}
+	}
+	if c.IsSet("older-than") {
+		age, err := lib.ParseAge(c.String("older-than"))
+		if err != nil {
+			return err
+		}
+		opts.OlderThan = age
+	}
+	if c.IsSet("max-size") {
+		size, err := lib.ParseSize(c.String("max-size"))
+		if err != nil {
+			return err
+		}
+		opts.MaxSize = size
+	}
+
+	// 保留工作区正在使用的Gradle版本
+	workspaces := c.StringSlice("workspace")
+	if len(workspaces) == 0 {
+		workspaces = lib.FindWorkspaces(WorkspacesPath)
+	}
+	for _, dir := range workspaces {
+		status := lib.CheckWorkspace(GradlePath, dir)
+		if status.Err == nil {
+			opts.Protected = append(opts.Protected, status.Version+"-"+status.Edition)
+		}
+	}
+
+	candidates, err := lib.PlanCachePrune(opts)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf(lib.T("清理缓存失败: %v"), err)
+	}
+
+	var reclaimed int64
+	for _, candidate := range candidates {
+		reclaimed += candidate.Size
+	}
+
+	if len(candidates) == 0 {
+		printMessage(lib.T("没有符合条件的缓存文件，无需清理"))
+		if outputJSON {
+			printJSON(map[string]any{"dry_run": c.Bool("dry-run"), "files": []lib.PruneCandidate{}, "deleted": []string{}, "reclaimed_bytes": 0})
+		}
+		return cli.Exit("", ExitNothingToDo)
+	}
+
+	printMessage(lib.T("即将删除 %d 个缓存文件:\n", len(candidates)))
+	for i, candidate := range candidates {
+		printMessage(fmt.Sprintf("  %d. %s (%s) - %s\n", i+1, candidate.File, lib.FormatBytes(candidate.Size), candidate.Reason))
+	}
+	printMessage(lib.T("可释放空间: %s\n", lib.FormatBytes(reclaimed)))
+
+	if c.Bool("dry-run") {
+		printMessage(lib.T("预演模式，未删除任何文件"))
+		if outputJSON {
+			printJSON(map[string]any{"dry_run": true, "files": candidates, "deleted": []string{}, "reclaimed_bytes": reclaimed})
+		}
+		return nil
+	}
+
+	ok, err := confirm(lib.T("\n确认删除这些文件吗？(y/N): "))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println(lib.T("操作已取消"))
+		return nil
+	}
+
+	deleted, err := lib.PruneCache(candidates, reporter)
+	if outputJSON {
+		if deleted == nil {
+			deleted = []string{}
+		}
+		printJSON(map[string]any{"dry_run": false, "files": candidates, "deleted": deleted, "reclaimed_bytes": reclaimed})
+	}
+	if err != nil {
+		return summaryExit(len(deleted), len(candidates)-len(deleted), fmt.Errorf(lib.T("清理缓存失败: %v"), err))
+	}
+
+	printMessage(lib.T("✅ 缓存清理完成"))
+	return nil
+}
+
+// 从 gradle 和 dists repair 命令的选项中读取处理选项
+func processOptions(c *cli.Context) lib.ProcessOptions {
+	return lib.ProcessOptions{
+		Unpack: c.Bool("unpack"),
+		Jobs:   c.Int("jobs"),
+		Force:  c.Bool("force"),
+		Wait:   c.Duration("wait"),
 	}
 }
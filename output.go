@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mcr_gradletools/lib"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// 程序退出码
+const (
+	ExitOK          = 0   // 成功
+	ExitFailure     = 1   // 全部失败，或参数、配置错误
+	ExitPartial     = 2   // 部分失败（例如部分Gradle版本处理失败）
+	ExitNothingToDo = 3   // 没有需要处理的内容
+	ExitInterrupted = 130 // 被Ctrl+C或SIGTERM中断
+)
+
+// 输出格式
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl" // 进度事件和结果均以JSON Lines输出
+)
+
+// 全局选项，在App.Before中根据命令行参数设置
+var (
+	outputFormat = OutputText
+	outputJSON   bool // 是否以JSON格式输出结果（json或jsonl）
+	assumeYes    bool // 是否对所有确认提示自动回答"是"
+
+	// 接收库函数的进度事件，根据输出格式选择实现
+	reporter lib.Reporter = lib.NewTerminalReporter(os.Stdout)
+)
+
+// JSON模式下命令失败时输出的结果
+type errorResult struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// 以JSON格式向标准输出打印结果
+// jsonl模式下结果作为type为result的一行输出，与进度事件区分
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	if outputFormat == OutputJSONL {
+		return encoder.Encode(struct {
+			Type   string `json:"type"`
+			Result any    `json:"result"`
+		}{"result", v})
+	}
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// 输出进度说明信息，与库函数的事件一样交给reporter处理
+func printMessage(message string) {
+	reporter.Report(lib.Event{Type: lib.EventMessage, Message: message})
+}
+
+// 询问用户确认，--yes时直接通过
+// JSON模式下无法交互，未指定--yes时返回错误
+func confirm(prompt string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if outputJSON {
+		return false, errors.New(lib.T("JSON输出模式下无法交互确认，请同时指定 --yes"))
+	}
+
+	fmt.Print(prompt)
+	var answer string
+	fmt.Scanln(&answer)
+
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// 根据命令返回的错误确定退出码
+func exitCodeOf(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr cli.ExitCoder
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return ExitFailure
+}
+
+// 根据各项的成功/失败数量确定退出状态，全部成功时返回nil
+func summaryExit(succeeded, failed int, err error) error {
+	switch {
+	case failed == 0 && succeeded == 0 && err == nil:
+		return cli.Exit("", ExitNothingToDo)
+	case failed == 0 && err == nil:
+		return nil
+	case succeeded > 0:
+		return cli.Exit(errorMessage(err), ExitPartial)
+	default:
+		return cli.Exit(errorMessage(err), ExitFailure)
+	}
+}
+
+// 获取错误信息，JSON模式下结果中已包含错误时不再重复输出
+func errorMessage(err error) string {
+	if err == nil || outputJSON {
+		return ""
+	}
+	return err.Error()
+}
+
+// 处理命令的返回错误：打印错误信息并以对应退出码退出
+func handleExit(err error) {
+	code := exitCodeOf(err)
+	if err != nil && err.Error() != "" {
+		if outputJSON {
+			printJSON(errorResult{Error: err.Error(), ExitCode: code})
+		} else {
+			log.Println(err)
+		}
+	}
+	os.Exit(code)
+}